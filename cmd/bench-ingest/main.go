@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-sms-broadcast/internal/adapters/db/postgres"
+	cfg "golang-sms-broadcast/internal/config"
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+// errRollback aborts each run's transaction so benchmarks leave no rows behind.
+var errRollback = errors.New("rollback benchmark run")
+
+type ingestPath struct {
+	name   string
+	insert func(repo *postgres.Repository, ctx context.Context, msgs []domain.Message) error
+}
+
+var paths = []ingestPath{
+	{"insert (CreateInBatches)", (*postgres.Repository).InsertMessages},
+	{"copy (COPY FROM)", (*postgres.Repository).CopyMessages},
+}
+
+func main() {
+	sizes := flag.String("sizes", "1000,10000,100000", "comma-separated recipient counts")
+	runs := flag.Int("runs", 3, "runs per path and size; the best run is reported")
+	flag.Parse()
+
+	conf := cfg.FromEnv()
+	repo, err := postgres.New(conf.DatabaseURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to connect to postgres: %v\n", err)
+		os.Exit(1)
+	}
+	defer repo.Close()
+
+	fmt.Println("\n📊 Recipient ingestion benchmark")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("%-26s %10s %12s %14s\n", "Path", "Messages", "Best", "Rows/sec")
+
+	for _, field := range strings.Split(*sizes, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n <= 0 {
+			fmt.Fprintf(os.Stderr, "❌ Invalid size %q\n", field)
+			os.Exit(1)
+		}
+
+		for _, path := range paths {
+			best, err := benchmark(repo, path, n, *runs)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s with %d messages: %v\n", path.name, n, err)
+				os.Exit(1)
+			}
+			fmt.Printf("%-26s %10d %12s %14.0f\n", path.name, n, best.Round(time.Millisecond), float64(n)/best.Seconds())
+		}
+	}
+	fmt.Println()
+}
+
+// benchmark inserts n messages through path inside a transaction that is
+// rolled back, and returns the fastest of runs attempts.
+func benchmark(repo *postgres.Repository, path ingestPath, n, runs int) (time.Duration, error) {
+	ctx := context.Background()
+	var best time.Duration
+
+	for i := 0; i < runs; i++ {
+		broadcast := domain.NewBroadcast(fmt.Sprintf("bench %s %d", path.name, n))
+		msgs := make([]domain.Message, 0, n)
+		for j := 0; j < n; j++ {
+			msgs = append(msgs, domain.NewMessage(broadcast.ID, fmt.Sprintf("+6680%07d", j), "Benchmark message"))
+		}
+
+		var elapsed time.Duration
+		err := repo.WithinTx(ctx, func(tx ports.MessageRepository) error {
+			if err := tx.SaveBroadcast(ctx, broadcast); err != nil {
+				return err
+			}
+
+			start := time.Now()
+			if err := path.insert(tx.(*postgres.Repository), ctx, msgs); err != nil {
+				return err
+			}
+			elapsed = time.Since(start)
+
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			return 0, err
+		}
+
+		if best == 0 || elapsed < best {
+			best = elapsed
+		}
+	}
+
+	return best, nil
+}
@@ -22,6 +22,9 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
+// defaultBodyLimit caps the request body of every route but broadcast creation.
+const defaultBodyLimit = 1024 * 1024
+
 func main() {
 	log := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
 	if err := run(log); err != nil {
@@ -45,19 +48,25 @@ func run(log *slog.Logger) error {
 	}
 	defer publisher.Close()
 
-	provider := httpmock.New(conf.ProviderURL)
-	svc := app.NewBroadcastService(repo, publisher, provider, log)
+	provider := httpmock.New(conf.ProviderURL, conf.ProviderName)
+	svc := app.NewBroadcastService(repo, repo, repo, repo, publisher, provider, conf.IdempotencyKeyTTL, conf.FrequencyCap(), conf.ShortLinkBaseURL, log)
 
 	fiberApp := fiber.New(fiber.Config{
 		AppName:               "broadcast-api",
 		DisableStartupMessage: true,
-		ReadTimeout:           10 * time.Second,
-		WriteTimeout:          10 * time.Second,
-		IdleTimeout:           120 * time.Second,
+		// Large recipient lists need longer than a typical request to upload
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
 		// OWASP: Disable server header to reduce information disclosure
 		ServerHeader: "",
-		// OWASP: Limit body size to prevent memory exhaustion attacks
-		BodyLimit: 1 * 1024 * 1024, // 1MB
+		// OWASP: Limit body size to prevent memory exhaustion attacks.
+		// Bodies are streamed so that middleware.BodyLimit below can refuse an
+		// oversized one before it is read; only the broadcast creation routes
+		// accept more than the default.
+		BodyLimit:                    defaultBodyLimit,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 
 	// ═══════════════════════════════════════════════════════════
@@ -89,6 +98,14 @@ func run(log *slog.Logger) error {
 	rateLimiter := middleware.NewRateLimiter(100, 1*time.Minute)
 	fiberApp.Use(rateLimiter.Middleware())
 
+	// 7. Body Limit - 1 MB, raised for large recipient lists.
+	// Sized for ~1M recipients (roughly 16 bytes each as JSON).
+	broadcastBodyLimit := conf.BroadcastBodyLimitMB * 1024 * 1024
+	fiberApp.Use(middleware.BodyLimit(defaultBodyLimit, map[string]int{
+		"POST /api/broadcasts":        broadcastBodyLimit,
+		"POST /api/broadcasts/upload": broadcastBodyLimit,
+	}))
+
 	// ═══════════════════════════════════════════════════════════
 	// Routes
 	// ═══════════════════════════════════════════════════════════
@@ -97,13 +114,33 @@ func run(log *slog.Logger) error {
 		return c.JSON(fiber.Map{"status": "healthy"})
 	})
 
-	handler := transport.NewHandler(svc, log)
+	// Jobs are only submitted here; cmd/job-worker runs them
+	jobs := app.NewJobService(repo, svc, 0, log)
+
+	contacts := app.NewContactService(repo, log)
+	suppressions := app.NewSuppressionService(repo, log)
+	senders := app.NewSenderService(repo, log)
+	otps := app.NewOTPService(repo, svc, conf.OTPPolicy(), conf.OTPSecret, log)
+
+	// Only the thread queries of the inbound service are used here
+	inbound := app.NewInboundService(repo, nil, nil, nil, nil, log)
+
+	handler := transport.NewHandler(svc, jobs, log)
 	api := fiberApp.Group("/api")
 	handler.Register(api)
+	transport.NewContactHandler(contacts, log).Register(api)
+	transport.NewSuppressionHandler(suppressions, log).Register(api)
+	transport.NewSenderHandler(senders, log).Register(api)
+	transport.NewOTPHandler(otps, log).Register(api)
+	transport.NewMessageHandler(svc, log).Register(api)
+	transport.NewConversationHandler(svc, inbound, log).Register(api)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// Drop idempotency keys once their retry window has passed
+	go purgeLoop(ctx, svc, 10*time.Minute, log)
+
 	errChan := make(chan error, 1)
 	go func() {
 		log.Info("broadcast-api started", "addr", conf.HTTPAddr)
@@ -129,3 +166,19 @@ func run(log *slog.Logger) error {
 	log.Info("broadcast-api stopped gracefully")
 	return nil
 }
+
+func purgeLoop(ctx context.Context, svc *app.BroadcastService, interval time.Duration, log *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := svc.PurgeExpiredIdempotencyKeys(ctx); err != nil {
+				log.Error("purge failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang-sms-broadcast/internal/adapters/db/postgres"
+	"golang-sms-broadcast/internal/adapters/webhook"
+	"golang-sms-broadcast/internal/app"
+	cfg "golang-sms-broadcast/internal/config"
+	"golang-sms-broadcast/internal/middleware"
+	"golang-sms-broadcast/internal/transport"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+func main() {
+	log := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		AddSource: true,
+	}))
+
+	if err := run(log); err != nil {
+		log.Error("application failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(log *slog.Logger) error {
+	conf := cfg.FromEnv()
+
+	adminAddr := conf.CallbackAdminAddr
+	pollInterval := conf.CallbackPollInterval
+	batchSize := conf.CallbackBatchSize
+	timeout := conf.CallbackTimeout
+	concurrency := max(conf.CallbackConcurrency, 1)
+	policy := app.CallbackPolicy{
+		MaxAttempts: conf.CallbackMaxAttempts,
+		BaseDelay:   conf.CallbackBackoffBase,
+		MaxDelay:    conf.CallbackBackoffMax,
+		Concurrency: concurrency,
+		// A claimed event becomes due again if we crash before recording the
+		// attempt. A batch goes out in ceil(batch/concurrency) rounds of at most
+		// one timeout each; one more timeout covers recording the attempts.
+		Lease: time.Duration((batchSize+concurrency-1)/concurrency+1) * timeout,
+	}
+
+	// ── Initialize dependencies ──────────────────────────────────────────────
+	repo, err := postgres.New(conf.DatabaseURL)
+	if err != nil {
+		return errors.New("failed to connect to postgres: " + err.Error())
+	}
+	defer repo.Close()
+
+	sender := webhook.New(conf.CallbackSigningSecret, timeout)
+	callbacks := app.NewCallbackService(repo, sender, policy, log)
+
+	// ── Admin API: delivery log and manual replay ────────────────────────────
+	fiberApp := fiber.New(fiber.Config{
+		AppName:               "callback-dispatcher",
+		DisableStartupMessage: true,
+		ReadTimeout:           10 * time.Second,
+		WriteTimeout:          10 * time.Second,
+		ServerHeader:          "",
+	})
+	fiberApp.Use(recover.New())
+	fiberApp.Use(middleware.RequestIDMiddleware())
+	fiberApp.Use(middleware.SecurityHeaders())
+
+	fiberApp.Get("/health", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "healthy"})
+	})
+	if conf.CallbackAdminToken == "" {
+		log.Warn("CALLBACK_ADMIN_TOKEN is empty; the admin API rejects every request")
+	}
+	admin := fiberApp.Group("", middleware.BearerToken(conf.CallbackAdminToken))
+	transport.NewCallbackHandler(callbacks, log).Register(admin)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := fiberApp.Listen(adminAddr); err != nil {
+			errChan <- err
+		}
+	}()
+
+	log.Info("callback-dispatcher started",
+		"admin_addr", adminAddr,
+		"poll_interval", pollInterval.String(),
+		"batch_size", batchSize,
+		"max_attempts", policy.MaxAttempts,
+		"lease", policy.Lease.String(),
+	)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("shutdown signal received")
+			break loop
+		case err := <-errChan:
+			return errors.New("admin server error: " + err.Error())
+		case <-ticker.C:
+			// Keep draining while full batches come back
+			for {
+				n, err := callbacks.DispatchDue(ctx, batchSize)
+				if err != nil {
+					log.Error("dispatch failed", "error", err)
+					break
+				}
+				if n < batchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := fiberApp.ShutdownWithContext(shutdownCtx); err != nil {
+		return errors.New("failed to shutdown gracefully: " + err.Error())
+	}
+
+	log.Info("callback-dispatcher stopped gracefully")
+	return nil
+}
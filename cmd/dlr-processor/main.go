@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"golang-sms-broadcast/internal/adapters/db/postgres"
+	"golang-sms-broadcast/internal/adapters/queue/rabbitmq"
+	"golang-sms-broadcast/internal/app"
+	cfg "golang-sms-broadcast/internal/config"
+	"golang-sms-broadcast/internal/domain"
+)
+
+func main() {
+	log := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		AddSource: true,
+	}))
+
+	if err := run(log); err != nil {
+		log.Error("application failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(log *slog.Logger) error {
+	conf := cfg.FromEnv()
+
+	batchSize := getEnvInt("DLR_BATCH_SIZE", 500)
+	flushInterval := getEnvDuration("DLR_FLUSH_INTERVAL", 1*time.Second)
+	pendingTTL := getEnvDuration("DLR_PENDING_TTL", 24*time.Hour)
+	purgeInterval := getEnvDuration("DLR_PURGE_INTERVAL", 10*time.Minute)
+
+	// ── Initialize dependencies ──────────────────────────────────────────────
+	repo, err := postgres.New(conf.DatabaseURL)
+	if err != nil {
+		return errors.New("failed to connect to postgres: " + err.Error())
+	}
+	defer repo.Close()
+
+	consumer, err := rabbitmq.NewDLRConsumer(conf.AMQPURL, batchSize, flushInterval, log)
+	if err != nil {
+		return errors.New("failed to connect to rabbitmq consumer: " + err.Error())
+	}
+	defer consumer.Close()
+
+	inboundConsumer, err := rabbitmq.NewInboundConsumer(conf.AMQPURL, log)
+	if err != nil {
+		return errors.New("failed to connect to rabbitmq consumer: " + err.Error())
+	}
+	defer inboundConsumer.Close()
+
+	rules, err := loadKeywordRules(conf.KeywordRulesFile)
+	if err != nil {
+		return errors.New("failed to load keyword rules: " + err.Error())
+	}
+
+	// DLR processor only applies receipts and inbound messages; it never enqueues them
+	receipts := app.NewReceiptService(repo, nil, pendingTTL, log)
+	inbound := app.NewInboundService(repo, repo, repo, nil, rules, log)
+
+	// ── Setup consumer ───────────────────────────────────────────────────────
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Info("dlr-processor started",
+		"batch_size", batchSize,
+		"flush_interval", flushInterval.String(),
+		"pending_ttl", pendingTTL.String(),
+		"keyword_rules", len(rules),
+	)
+
+	// Periodically drop parked receipts that never matched a message
+	go purgeLoop(ctx, receipts, purgeInterval, log)
+
+	// Inbound messages are consumed alongside receipts; a fatal error in
+	// either consumer stops the process.
+	inboundErr := make(chan error, 1)
+	go func() {
+		inboundErr <- inboundConsumer.ConsumeInbound(ctx, inbound.HandleInbound)
+		stop()
+	}()
+
+	// ConsumeBatches blocks until context is cancelled or fatal error
+	err = consumer.ConsumeBatches(ctx, receipts.ApplyDLRBatch)
+	if ctx.Err() != nil {
+		select {
+		case iErr := <-inboundErr:
+			if iErr != nil && !errors.Is(iErr, context.Canceled) {
+				return errors.New("inbound consumer error: " + iErr.Error())
+			}
+		default:
+		}
+	}
+	if err != nil {
+		// If context was cancelled, it's a graceful shutdown
+		if ctx.Err() != nil {
+			log.Info("shutdown signal received")
+			return nil
+		}
+		return errors.New("consumer error: " + err.Error())
+	}
+
+	log.Info("dlr-processor stopped gracefully")
+	return nil
+}
+
+func purgeLoop(ctx context.Context, receipts *app.ReceiptService, interval time.Duration, log *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := receipts.PurgeExpiredDLRs(ctx); err != nil {
+				log.Error("purge failed", "error", err)
+			}
+		}
+	}
+}
+
+// loadKeywordRules reads the rules file, or returns the built-in rules when
+// no file is configured.
+func loadKeywordRules(path string) ([]domain.KeywordRule, error) {
+	if path == "" {
+		return domain.DefaultKeywordRules(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ParseKeywordRules(data)
+}
+
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return def
+	}
+
+	return d
+}
+
+func getEnvInt(key string, def int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return def
+	}
+
+	return i
+}
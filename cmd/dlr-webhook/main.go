@@ -6,10 +6,12 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
-	"golang-sms-broadcast/internal/adapters/db/postgres"
+	"golang-sms-broadcast/internal/adapters/dlr"
+	"golang-sms-broadcast/internal/adapters/queue/rabbitmq"
 	"golang-sms-broadcast/internal/app"
 	cfg "golang-sms-broadcast/internal/config"
 	"golang-sms-broadcast/internal/middleware"
@@ -32,13 +34,22 @@ func run(log *slog.Logger) error {
 	conf := cfg.FromEnv()
 	addr := getenvOrDefault("DLR_WEBHOOK_ADDR", ":8081")
 
-	repo, err := postgres.New(conf.DatabaseURL)
+	// Receipts and inbound messages are only parsed and enqueued here;
+	// dlr-processor applies them.
+	queue, err := rabbitmq.NewDLRPublisher(conf.AMQPURL)
 	if err != nil {
-		return errors.New("failed to connect to postgres: " + err.Error())
+		return errors.New("failed to connect to rabbitmq: " + err.Error())
 	}
-	defer repo.Close()
+	defer queue.Close()
 
-	svc := app.NewBroadcastService(repo, nil, nil, log)
+	inboundQueue, err := rabbitmq.NewInboundPublisher(conf.AMQPURL)
+	if err != nil {
+		return errors.New("failed to connect to rabbitmq: " + err.Error())
+	}
+	defer inboundQueue.Close()
+
+	receipts := app.NewReceiptService(nil, queue, 0, log)
+	inbound := app.NewInboundService(nil, nil, nil, inboundQueue, nil, log)
 
 	fiberApp := fiber.New(fiber.Config{
 		AppName:               "dlr-webhook",
@@ -56,16 +67,28 @@ func run(log *slog.Logger) error {
 	fiberApp.Use(middleware.RequestIDMiddleware())
 	fiberApp.Use(middleware.SecurityHeaders())
 
-	// Rate limiting for webhook endpoint (200 req/min per IP)
-	rateLimiter := middleware.NewRateLimiter(200, 1*time.Minute)
+	// Rate limiting for webhook endpoint (default 200 req/min per IP).
+	// Providers post from few IPs, so raise this for large campaigns.
+	rateLimiter := middleware.NewRateLimiter(getenvInt("DLR_RATE_LIMIT", 200), 1*time.Minute)
 	fiberApp.Use(rateLimiter.Middleware())
 
 	fiberApp.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"status": "healthy"})
 	})
 
-	handler := transport.NewHandler(svc, log)
-	fiberApp.Post("/dlr", handler.HandleDLR)
+	// Only accept receipts signed with the provider's shared secret
+	verify := middleware.VerifySignature(middleware.SignatureConfig{
+		Secrets:         conf.DLRSigningSecrets,
+		DefaultProvider: "mock",
+		Tolerance:       conf.DLRSignatureTolerance,
+	})
+
+	// Inbound messages share the providers' signing secrets
+	inboundHandler := transport.NewInboundHandler(inbound, dlr.InboundParsers(), log)
+	inboundHandler.Register(fiberApp, verify)
+
+	handler := transport.NewDLRHandler(receipts, dlr.Parsers(), inboundHandler, log)
+	handler.Register(fiberApp, verify)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -102,3 +125,11 @@ func getenvOrDefault(key, def string) string {
 	}
 	return def
 }
+
+func getenvInt(key string, def int) int {
+	i, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return i
+}
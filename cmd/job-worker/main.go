@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang-sms-broadcast/internal/adapters/db/postgres"
+	"golang-sms-broadcast/internal/app"
+	cfg "golang-sms-broadcast/internal/config"
+)
+
+func main() {
+	log := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		AddSource: true,
+	}))
+
+	if err := run(log); err != nil {
+		log.Error("application failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(log *slog.Logger) error {
+	conf := cfg.FromEnv()
+
+	pollInterval := getEnvDuration("JOB_POLL_INTERVAL", 1*time.Second)
+	staleAfter := getEnvDuration("JOB_STALE_AFTER", 5*time.Minute)
+
+	// ── Initialize dependencies ──────────────────────────────────────────────
+	repo, err := postgres.New(conf.DatabaseURL)
+	if err != nil {
+		return errors.New("failed to connect to postgres: " + err.Error())
+	}
+	defer repo.Close()
+
+	// Job worker only writes to the outbox; outbox-publisher does the rest
+	broadcasts := app.NewBroadcastService(repo, repo, repo, repo, nil, nil, 0, conf.FrequencyCap(), conf.ShortLinkBaseURL, log)
+	jobs := app.NewJobService(repo, broadcasts, staleAfter, log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Info("job-worker started",
+		"poll_interval", pollInterval.String(),
+		"stale_after", staleAfter.String(),
+	)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("job-worker stopped gracefully")
+			return nil
+		case <-ticker.C:
+			// Run jobs back to back until the queue is empty
+			for ctx.Err() == nil {
+				found, err := jobs.RunNext(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Error("job failed", "error", err)
+					}
+					break
+				}
+				if !found {
+					break
+				}
+			}
+		}
+	}
+}
+
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return def
+	}
+
+	return d
+}
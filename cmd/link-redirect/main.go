@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang-sms-broadcast/internal/adapters/db/postgres"
+	"golang-sms-broadcast/internal/app"
+	cfg "golang-sms-broadcast/internal/config"
+	"golang-sms-broadcast/internal/middleware"
+	"golang-sms-broadcast/internal/transport"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+func main() {
+	log := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	if err := run(log); err != nil {
+		log.Error("application failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(log *slog.Logger) error {
+	conf := cfg.FromEnv()
+	addr := conf.LinkRedirectAddr
+
+	repo, err := postgres.New(conf.DatabaseURL)
+	if err != nil {
+		return errors.New("failed to connect to postgres: " + err.Error())
+	}
+	defer repo.Close()
+
+	links := app.NewLinkService(repo, log)
+
+	fiberApp := fiber.New(fiber.Config{
+		AppName:               "link-redirect",
+		DisableStartupMessage: true,
+		ReadTimeout:           5 * time.Second,
+		WriteTimeout:          5 * time.Second,
+		IdleTimeout:           60 * time.Second,
+		ServerHeader:          "",
+		BodyLimit:             4 * 1024, // Redirects carry no body
+	})
+
+	// Security Middleware
+	fiberApp.Use(recover.New())
+	fiberApp.Use(logger.New())
+	fiberApp.Use(middleware.RequestIDMiddleware())
+	fiberApp.Use(middleware.SecurityHeaders())
+
+	// Only lookups of unknown codes count against the per-IP limit (default
+	// 60/min), which slows down guessing codes without refusing the many
+	// recipients that share one carrier NAT address.
+	rateLimiter := middleware.NewRateLimiter(conf.LinkRateLimit, 1*time.Minute)
+	fiberApp.Use(rateLimiter.FailureMiddleware(fiber.StatusNotFound))
+
+	fiberApp.Get("/health", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "healthy"})
+	})
+	transport.NewLinkHandler(links, log).Register(fiberApp)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	errChan := make(chan error, 1)
+	go func() {
+		log.Info("link-redirect started", "addr", addr)
+		if err := fiberApp.Listen(addr); err != nil {
+			errChan <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Info("shutdown signal received")
+	case err := <-errChan:
+		return err
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := fiberApp.ShutdownWithContext(shutdownCtx); err != nil {
+		return errors.New("failed to shutdown gracefully: " + err.Error())
+	}
+
+	log.Info("link-redirect stopped gracefully")
+	return nil
+}
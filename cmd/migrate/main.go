@@ -34,7 +34,7 @@ func main() {
 	fmt.Println("✅ Connected to database")
 	fmt.Println("🔄 Running migrations...")
 
-	if err := db.AutoMigrate(&domain.Broadcast{}, &domain.Message{}); err != nil {
+	if err := db.AutoMigrate(&domain.Broadcast{}, &domain.Message{}, &domain.PendingDLR{}, &domain.CallbackEvent{}, &domain.CallbackAttempt{}, &domain.IdempotencyKey{}, &domain.BroadcastJob{}, &domain.BroadcastJobChunk{}, &domain.Contact{}, &domain.ContactList{}, &domain.ContactListMember{}, &domain.Suppression{}, &domain.InboundMessage{}, &domain.SenderID{}, &domain.OTP{}, &domain.ShortLink{}, &domain.LinkClick{}); err != nil {
 		log.Fatalf("❌ Migration failed: %v", err)
 	}
 
@@ -4,13 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"math/rand"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"golang-sms-broadcast/internal/middleware"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
@@ -19,6 +24,7 @@ import (
 type mockSendRequest struct {
 	MessageID string `json:"message_id"`
 	To        string `json:"to"`
+	From      string `json:"from"`
 	Body      string `json:"body"`
 	DLRHook   string `json:"dlr_webhook_url"`
 }
@@ -33,20 +39,45 @@ func main() {
 
 	addr := getenv("HTTP_ADDR", ":9090")
 	dlrHook := getenv("DLR_WEBHOOK_URL", "http://localhost:8081/dlr")
+	dlrSecret := getenv("DLR_SIGNING_SECRET", "dev-dlr-secret")
+
+	initial, err := loadScenario(getenv("MOCK_SCENARIO", "happy"), os.Getenv("MOCK_SCENARIO_FILE"))
+	if err != nil {
+		log.Error("load scenario", "err", err)
+		os.Exit(1)
+	}
+	scenarios := NewScenarioStore(initial)
+	log.Info("mock scenario active", "scenario", initial.Name)
 
 	fiberApp := fiber.New(fiber.Config{AppName: "mock-sms-provider"})
 
 	// POST /send — accepts an SMS submission and echoes back a generated provider ID.
+	// The response and the follow-up DLR are shaped by the active scenario.
 	fiberApp.Post("/send", func(c *fiber.Ctx) error {
 		var req mockSendRequest
 		if err := c.BodyParser(&req); err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
 		}
 
+		sc := scenarios.Get()
+		time.Sleep(sc.Submit.Latency.Sample())
+
+		rule, matched := sc.match(req.To)
+		if matched && rule.SubmitStatus != 0 {
+			log.Info("mock provider rejected message by rule", "message_id", req.MessageID, "to", req.To, "status", rule.SubmitStatus)
+			return c.Status(rule.SubmitStatus).JSON(fiber.Map{"error": "rejected by number rule"})
+		}
+
+		if status, ok := injectedError(sc.Submit); ok {
+			log.Info("mock provider injected error", "message_id", req.MessageID, "to", req.To, "status", status)
+			return c.Status(status).JSON(fiber.Map{"error": "injected failure"})
+		}
+
 		providerID := uuid.New().String()
 		log.Info("mock provider received message",
 			"message_id", req.MessageID,
 			"to", req.To,
+			"from", req.From,
 			"provider_id", providerID,
 		)
 
@@ -55,11 +86,52 @@ func main() {
 		if hook == "" {
 			hook = dlrHook
 		}
-		go simulateDLR(hook, providerID, log)
+		scheduleDLR(sc, rule, matched, hook, dlrSecret, providerID, log)
 
 		return c.Status(fiber.StatusAccepted).JSON(mockSendResponse{ProviderID: providerID})
 	})
 
+	// ── Admin API ─────────────────────────────────────────────────────────────
+
+	// GET /admin/scenario — returns the active scenario.
+	fiberApp.Get("/admin/scenario", func(c *fiber.Ctx) error {
+		return c.JSON(scenarios.Get())
+	})
+
+	// PUT /admin/scenario — replaces the active scenario with a custom one.
+	fiberApp.Put("/admin/scenario", func(c *fiber.Ctx) error {
+		var sc Scenario
+		if err := json.Unmarshal(c.Body(), &sc); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+		}
+		if sc.Name == "" {
+			sc.Name = "custom"
+		}
+		if err := scenarios.Set(sc); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		log.Info("mock scenario switched", "scenario", sc.Name)
+		return c.JSON(sc)
+	})
+
+	// GET /admin/scenarios — lists the built-in presets.
+	fiberApp.Get("/admin/scenarios", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"presets": presetNames()})
+	})
+
+	// POST /admin/scenarios/:name — activates a built-in preset.
+	fiberApp.Post("/admin/scenarios/:name", func(c *fiber.Ctx) error {
+		sc, ok := presets[c.Params("name")]
+		if !ok {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "unknown scenario"})
+		}
+		if err := scenarios.Set(sc); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		log.Info("mock scenario switched", "scenario", sc.Name)
+		return c.JSON(sc)
+	})
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
@@ -75,13 +147,85 @@ func main() {
 	_ = fiberApp.Shutdown()
 }
 
-// simulateDLR posts a delivery receipt to the DLR webhook after a short delay.
-func simulateDLR(hookURL, providerID string, log *slog.Logger) {
-	time.Sleep(500 * time.Millisecond) // simulate async network delivery
+// loadScenario returns the scenario from file if given, otherwise the named preset.
+func loadScenario(name, file string) (Scenario, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return Scenario{}, fmt.Errorf("read scenario file: %w", err)
+		}
+		var sc Scenario
+		if err := json.Unmarshal(data, &sc); err != nil {
+			return Scenario{}, fmt.Errorf("parse scenario file: %w", err)
+		}
+		if sc.Name == "" {
+			sc.Name = file
+		}
+		return sc, sc.Validate()
+	}
+
+	sc, ok := presets[name]
+	if !ok {
+		return Scenario{}, fmt.Errorf("unknown scenario %q (available: %v)", name, presetNames())
+	}
+	return sc, nil
+}
+
+// injectedError rolls the configured 4xx/5xx percentages.
+func injectedError(b SubmitBehaviour) (int, bool) {
+	roll := rand.Float64() * 100
+	switch {
+	case roll < b.ClientErrorPct:
+		return fiber.StatusBadRequest, true
+	case roll < b.ClientErrorPct+b.ServerErrorPct:
+		return fiber.StatusServiceUnavailable, true
+	default:
+		return 0, false
+	}
+}
+
+// scheduleDLR decides the receipt outcome and fires zero, one or two callbacks.
+func scheduleDLR(sc Scenario, rule NumberRule, matched bool, hookURL, secret, providerID string, log *slog.Logger) {
+	if (matched && rule.DropDLR) || chance(sc.DLR.MissingPct) {
+		log.Info("dlr suppressed by scenario", "provider_id", providerID)
+		return
+	}
+
+	status := sc.DLR.pickOutcome()
+	if matched && rule.DLRStatus != "" {
+		status = rule.DLRStatus
+	}
+
+	copies := 1
+	if chance(sc.DLR.DuplicatePct) {
+		copies = 2
+	}
+
+	for i := 0; i < copies; i++ {
+		go simulateDLR(hookURL, secret, providerID, status, sc.DLR.Delay.Sample(), log)
+	}
+}
+
+// dlrErrors are the error details reported with each non-delivered outcome.
+var dlrErrors = map[string]struct{ code, description string }{
+	"failed":        {"001", "Delivery failed"},
+	"expired":       {"002", "Validity period expired"},
+	"undeliverable": {"003", "Unknown subscriber"},
+}
+
+// simulateDLR posts a signed delivery receipt to the DLR webhook after the given delay.
+func simulateDLR(hookURL, secret, providerID, status string, delay time.Duration, log *slog.Logger) {
+	time.Sleep(delay) // simulate async network delivery
 
 	payload := map[string]string{
 		"provider_id": providerID,
-		"status":      "delivered",
+		"status":      status,
+	}
+	if status == "delivered" {
+		payload["delivered_at"] = time.Now().UTC().Format(time.RFC3339)
+	} else if e, ok := dlrErrors[status]; ok {
+		payload["error_code"] = e.code
+		payload["error_description"] = e.description
 	}
 	body, _ := json.Marshal(payload)
 
@@ -95,13 +239,19 @@ func simulateDLR(hookURL, providerID string, log *slog.Logger) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
+	ts := time.Now().Unix()
+	req.Header.Set(middleware.SignatureTimestampHeader, strconv.FormatInt(ts, 10))
+	nonce := uuid.NewString()
+	req.Header.Set(middleware.SignatureNonceHeader, nonce)
+	req.Header.Set(middleware.SignatureHeader, middleware.Sign(secret, ts, nonce, body))
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		log.Error("dlr webhook call failed", "provider_id", providerID, "err", err)
 		return
 	}
 	defer resp.Body.Close()
-	log.Info("dlr webhook called", "provider_id", providerID, "status", resp.StatusCode)
+	log.Info("dlr webhook called", "provider_id", providerID, "dlr_status", status, "status", resp.StatusCode)
 }
 
 func getenv(k, def string) string {
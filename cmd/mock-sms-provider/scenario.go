@@ -0,0 +1,293 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scenario describes how the mock provider answers submissions and which
+// delivery receipts it sends back. It can be swapped at runtime through the
+// admin endpoints so retry and reconciliation paths can be exercised.
+type Scenario struct {
+	Name   string          `json:"name"`
+	Submit SubmitBehaviour `json:"submit"`
+	DLR    DLRBehaviour    `json:"dlr"`
+	Rules  []NumberRule    `json:"rules,omitempty"`
+}
+
+// SubmitBehaviour controls the synchronous response to POST /send.
+type SubmitBehaviour struct {
+	ClientErrorPct float64 `json:"client_error_pct"` // % of submissions rejected with 400
+	ServerErrorPct float64 `json:"server_error_pct"` // % of submissions rejected with 503
+	Latency        Latency `json:"latency"`
+}
+
+// DLRBehaviour controls the asynchronous delivery receipt callbacks.
+type DLRBehaviour struct {
+	// Outcomes maps a DLR status to its relative weight, e.g. {"delivered": 90, "failed": 10}.
+	Outcomes     map[string]float64 `json:"outcomes"`
+	Delay        Latency            `json:"delay"`
+	MissingPct   float64            `json:"missing_pct"`   // % of receipts never sent
+	DuplicatePct float64            `json:"duplicate_pct"` // % of receipts sent twice
+}
+
+// NumberRule overrides the random behaviour for recipients matching a prefix
+// and/or suffix, e.g. numbers ending in 0000 always fail.
+type NumberRule struct {
+	Prefix       string `json:"prefix,omitempty"`
+	Suffix       string `json:"suffix,omitempty"`
+	SubmitStatus int    `json:"submit_status,omitempty"` // 4xx/5xx returned instead of 202
+	DLRStatus    string `json:"dlr_status,omitempty"`    // forced receipt status
+	DropDLR      bool   `json:"drop_dlr,omitempty"`      // never send a receipt
+}
+
+// Latency is a delay distribution expressed in milliseconds.
+type Latency struct {
+	Distribution string `json:"distribution"` // fixed | uniform | normal | exponential
+	MinMS        int    `json:"min_ms,omitempty"`
+	MaxMS        int    `json:"max_ms,omitempty"`
+	MeanMS       int    `json:"mean_ms,omitempty"`
+	StdDevMS     int    `json:"stddev_ms,omitempty"`
+}
+
+// dlrStatuses are the receipt outcomes the mock knows how to emit.
+var dlrStatuses = map[string]bool{
+	"delivered":     true,
+	"failed":        true,
+	"expired":       true,
+	"undeliverable": true,
+}
+
+// presets are the built-in scenarios selectable by name.
+var presets = map[string]Scenario{
+	"happy": {
+		Name:   "happy",
+		Submit: SubmitBehaviour{Latency: Latency{Distribution: "fixed"}},
+		DLR: DLRBehaviour{
+			Outcomes: map[string]float64{"delivered": 100},
+			Delay:    Latency{Distribution: "fixed", MeanMS: 500},
+		},
+	},
+	"flaky": {
+		Name: "flaky",
+		Submit: SubmitBehaviour{
+			ClientErrorPct: 2,
+			ServerErrorPct: 5,
+			Latency:        Latency{Distribution: "uniform", MinMS: 50, MaxMS: 300},
+		},
+		DLR: DLRBehaviour{
+			Outcomes:     map[string]float64{"delivered": 90, "failed": 5, "expired": 3, "undeliverable": 2},
+			Delay:        Latency{Distribution: "exponential", MinMS: 200, MeanMS: 1500},
+			MissingPct:   2,
+			DuplicatePct: 2,
+		},
+		Rules: []NumberRule{{Suffix: "0000", DLRStatus: "failed"}},
+	},
+	"slow": {
+		Name:   "slow",
+		Submit: SubmitBehaviour{Latency: Latency{Distribution: "normal", MeanMS: 2000, StdDevMS: 500}},
+		DLR: DLRBehaviour{
+			Outcomes: map[string]float64{"delivered": 100},
+			Delay:    Latency{Distribution: "uniform", MinMS: 5000, MaxMS: 30000},
+		},
+	},
+	"outage": {
+		Name:   "outage",
+		Submit: SubmitBehaviour{ServerErrorPct: 100, Latency: Latency{Distribution: "fixed"}},
+		DLR:    DLRBehaviour{Outcomes: map[string]float64{"delivered": 100}},
+	},
+	"chaos": {
+		Name: "chaos",
+		Submit: SubmitBehaviour{
+			ClientErrorPct: 5,
+			ServerErrorPct: 15,
+			Latency:        Latency{Distribution: "exponential", MeanMS: 400},
+		},
+		DLR: DLRBehaviour{
+			Outcomes:     map[string]float64{"delivered": 60, "failed": 20, "expired": 10, "undeliverable": 10},
+			Delay:        Latency{Distribution: "uniform", MinMS: 0, MaxMS: 10000},
+			MissingPct:   10,
+			DuplicatePct: 10,
+		},
+		Rules: []NumberRule{
+			{Suffix: "0000", DLRStatus: "failed"},
+			{Suffix: "9999", SubmitStatus: 400},
+			{Suffix: "8888", DropDLR: true},
+		},
+	},
+}
+
+// presetNames returns the preset names in a stable order.
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that percentages, statuses and distributions are sane.
+func (s Scenario) Validate() error {
+	for field, pct := range map[string]float64{
+		"submit.client_error_pct": s.Submit.ClientErrorPct,
+		"submit.server_error_pct": s.Submit.ServerErrorPct,
+		"dlr.missing_pct":         s.DLR.MissingPct,
+		"dlr.duplicate_pct":       s.DLR.DuplicatePct,
+	} {
+		if pct < 0 || pct > 100 {
+			return fmt.Errorf("%s must be between 0 and 100", field)
+		}
+	}
+	if s.Submit.ClientErrorPct+s.Submit.ServerErrorPct > 100 {
+		return errors.New("submit error percentages must not exceed 100 in total")
+	}
+
+	if err := s.Submit.Latency.validate(); err != nil {
+		return fmt.Errorf("submit.latency: %w", err)
+	}
+	if err := s.DLR.Delay.validate(); err != nil {
+		return fmt.Errorf("dlr.delay: %w", err)
+	}
+
+	total := 0.0
+	for status, weight := range s.DLR.Outcomes {
+		if !dlrStatuses[status] {
+			return fmt.Errorf("unknown dlr outcome %q", status)
+		}
+		if weight < 0 {
+			return fmt.Errorf("dlr outcome %q has negative weight", status)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return errors.New("dlr.outcomes must contain at least one positive weight")
+	}
+
+	for i, r := range s.Rules {
+		if r.Prefix == "" && r.Suffix == "" {
+			return fmt.Errorf("rules[%d]: prefix or suffix is required", i)
+		}
+		if r.SubmitStatus != 0 && (r.SubmitStatus < 400 || r.SubmitStatus > 599) {
+			return fmt.Errorf("rules[%d]: invalid submit_status %d", i, r.SubmitStatus)
+		}
+		if r.DLRStatus != "" && !dlrStatuses[r.DLRStatus] {
+			return fmt.Errorf("rules[%d]: unknown dlr_status %q", i, r.DLRStatus)
+		}
+	}
+
+	return nil
+}
+
+// match returns the first rule whose prefix and suffix both match the number.
+func (s Scenario) match(to string) (NumberRule, bool) {
+	for _, r := range s.Rules {
+		if strings.HasPrefix(to, r.Prefix) && strings.HasSuffix(to, r.Suffix) {
+			return r, true
+		}
+	}
+	return NumberRule{}, false
+}
+
+// pickOutcome draws a DLR status according to the configured weights.
+func (d DLRBehaviour) pickOutcome() string {
+	total := 0.0
+	for _, w := range d.Outcomes {
+		total += w
+	}
+
+	// Iterate in a stable order so a given roll always maps to the same status.
+	statuses := make([]string, 0, len(d.Outcomes))
+	for status := range d.Outcomes {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	roll := rand.Float64() * total
+	for _, status := range statuses {
+		roll -= d.Outcomes[status]
+		if roll < 0 {
+			return status
+		}
+	}
+	return statuses[len(statuses)-1]
+}
+
+func (l Latency) validate() error {
+	if l.MinMS < 0 || l.MaxMS < 0 || l.MeanMS < 0 || l.StdDevMS < 0 {
+		return errors.New("durations must not be negative")
+	}
+	switch l.Distribution {
+	case "", "fixed", "normal", "exponential":
+		return nil
+	case "uniform":
+		if l.MaxMS < l.MinMS {
+			return errors.New("max_ms must be >= min_ms")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown distribution %q", l.Distribution)
+	}
+}
+
+// Sample draws a single delay from the distribution.
+func (l Latency) Sample() time.Duration {
+	var ms float64
+	switch l.Distribution {
+	case "uniform":
+		ms = float64(l.MinMS) + rand.Float64()*float64(l.MaxMS-l.MinMS)
+	case "normal":
+		ms = float64(l.MeanMS) + rand.NormFloat64()*float64(l.StdDevMS)
+	case "exponential":
+		ms = float64(l.MinMS) + rand.ExpFloat64()*float64(l.MeanMS)
+	default: // fixed
+		ms = float64(l.MeanMS)
+	}
+
+	if ms < float64(l.MinMS) {
+		ms = float64(l.MinMS)
+	}
+	if l.MaxMS > 0 && ms > float64(l.MaxMS) {
+		ms = float64(l.MaxMS)
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// chance returns true with the given percentage probability.
+func chance(pct float64) bool {
+	return pct > 0 && rand.Float64()*100 < pct
+}
+
+// ScenarioStore holds the active scenario and allows it to be swapped safely.
+type ScenarioStore struct {
+	mu      sync.RWMutex
+	current Scenario
+}
+
+// NewScenarioStore creates a store starting with the given scenario.
+func NewScenarioStore(s Scenario) *ScenarioStore {
+	return &ScenarioStore{current: s}
+}
+
+// Get returns the active scenario.
+func (st *ScenarioStore) Get() Scenario {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.current
+}
+
+// Set validates and activates a new scenario.
+func (st *ScenarioStore) Set(s Scenario) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	st.mu.Lock()
+	st.current = s
+	st.mu.Unlock()
+	return nil
+}
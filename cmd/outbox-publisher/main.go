@@ -14,6 +14,7 @@ import (
 	"golang-sms-broadcast/internal/adapters/queue/rabbitmq"
 	"golang-sms-broadcast/internal/app"
 	cfg "golang-sms-broadcast/internal/config"
+	"golang-sms-broadcast/internal/domain"
 )
 
 func main() {
@@ -47,8 +48,8 @@ func run(log *slog.Logger) error {
 	}
 	defer publisher.Close()
 
-	// Outbox publisher doesn't need provider
-	svc := app.NewBroadcastService(repo, publisher, nil, log)
+	// Outbox publisher doesn't need provider; the frequency cap is applied at creation and send time
+	svc := app.NewBroadcastService(repo, nil, nil, nil, publisher, nil, 0, domain.FrequencyCap{}, "", log)
 
 	// ── Setup polling loop ───────────────────────────────────────────────────
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -43,10 +43,10 @@ func run(log *slog.Logger) error {
 	}
 	defer consumer.Close()
 
-	provider := httpmock.New(conf.ProviderURL)
+	provider := httpmock.New(conf.ProviderURL, conf.ProviderName)
 
 	// Sender worker doesn't need publisher
-	svc := app.NewBroadcastService(repo, nil, provider, log)
+	svc := app.NewBroadcastService(repo, nil, repo, nil, nil, provider, 0, conf.FrequencyCap(), "", log)
 
 	// ── Setup consumer ───────────────────────────────────────────────────────
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -0,0 +1,6 @@
+-- 002_dlr_details.sql
+-- Delivery receipt details captured from provider DLRs.
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS error_code        TEXT;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS error_description TEXT;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS delivered_at      TIMESTAMPTZ;
@@ -0,0 +1,22 @@
+-- 003_pending_dlrs.sql
+-- Receipts that arrived before the sender-worker stored the provider ID.
+
+CREATE TABLE IF NOT EXISTS pending_dlrs (
+    id                BIGSERIAL   PRIMARY KEY,
+    provider          TEXT        NOT NULL,
+    provider_id       TEXT        NOT NULL,
+    status            TEXT        NOT NULL,
+    error_code        TEXT,
+    error_description TEXT,
+    delivered_at      TIMESTAMPTZ,
+    received_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at        TIMESTAMPTZ NOT NULL
+);
+
+-- Index for replaying parked receipts when SetProviderID attaches the ID.
+CREATE INDEX IF NOT EXISTS idx_pending_dlrs_provider_id
+    ON pending_dlrs (provider_id);
+
+-- Index for purging receipts that never matched.
+CREATE INDEX IF NOT EXISTS idx_pending_dlrs_expires_at
+    ON pending_dlrs (expires_at);
@@ -0,0 +1,45 @@
+-- 004_callbacks.sql
+-- Outbound status callbacks to client applications.
+
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS callback_url TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS callback_events (
+    id              UUID        PRIMARY KEY,
+    broadcast_id    UUID        NOT NULL,
+    message_id      UUID,
+    event           TEXT        NOT NULL,
+    url             TEXT        NOT NULL,
+    payload         JSONB       NOT NULL,
+    dedup_key       TEXT        NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'pending',
+    attempts        INT         NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL
+);
+
+-- Each message status (and each broadcast completion) fires once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_callback_events_dedup
+    ON callback_events (dedup_key);
+
+-- Index for the dispatcher's due-event poll.
+CREATE INDEX IF NOT EXISTS idx_callback_events_due
+    ON callback_events (status, next_attempt_at);
+
+CREATE INDEX IF NOT EXISTS idx_callback_events_broadcast
+    ON callback_events (broadcast_id);
+
+CREATE TABLE IF NOT EXISTS callback_attempts (
+    id           BIGSERIAL   PRIMARY KEY,
+    event_id     UUID        NOT NULL,
+    attempt      INT         NOT NULL,
+    status_code  INT,
+    error        TEXT,
+    duration_ms  BIGINT      NOT NULL,
+    attempted_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_callback_attempts_event
+    ON callback_attempts (event_id);
@@ -0,0 +1,15 @@
+-- 005_idempotency_keys.sql
+-- Idempotency-Key records for POST /api/broadcasts.
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key          TEXT        PRIMARY KEY,
+    fingerprint  TEXT        NOT NULL,
+    broadcast_id UUID        NOT NULL,
+    queued       INT         NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+-- Index for purging keys past their retry window.
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at
+    ON idempotency_keys (expires_at);
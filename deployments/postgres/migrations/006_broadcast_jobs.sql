@@ -0,0 +1,26 @@
+-- 006_broadcast_jobs.sql
+-- Background broadcast creation for large recipient lists.
+
+CREATE TABLE IF NOT EXISTS broadcast_jobs (
+    id           UUID        PRIMARY KEY,
+    status       TEXT        NOT NULL DEFAULT 'queued',
+    name         TEXT        NOT NULL,
+    body         TEXT        NOT NULL,
+    callback_url TEXT        NOT NULL DEFAULT '',
+    phone_column TEXT        NOT NULL DEFAULT '',
+    input        BYTEA       NOT NULL,
+    total_rows   INT         NOT NULL DEFAULT 0,
+    queued       INT         NOT NULL DEFAULT 0,
+    rejected     INT         NOT NULL DEFAULT 0,
+    errors       JSONB       NOT NULL DEFAULT '[]',
+    error        TEXT,
+    broadcast_id UUID,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL,
+    started_at   TIMESTAMPTZ,
+    finished_at  TIMESTAMPTZ
+);
+
+-- Index for the job-worker's claim poll.
+CREATE INDEX IF NOT EXISTS idx_broadcast_jobs_status
+    ON broadcast_jobs (status);
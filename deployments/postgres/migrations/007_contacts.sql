@@ -0,0 +1,33 @@
+-- 007_contacts.sql
+-- Reusable contacts and contact lists.
+
+CREATE TABLE IF NOT EXISTS contacts (
+    id         UUID        PRIMARY KEY,
+    phone      TEXT        NOT NULL,
+    attributes JSONB       NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- One contact per phone number; bulk imports upsert on it.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_phone
+    ON contacts (phone);
+
+CREATE TABLE IF NOT EXISTS contact_lists (
+    id          UUID        PRIMARY KEY,
+    name        TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS contact_list_members (
+    list_id    UUID        NOT NULL REFERENCES contact_lists (id) ON DELETE CASCADE,
+    contact_id UUID        NOT NULL REFERENCES contacts (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (list_id, contact_id)
+);
+
+-- Index for removing a deleted contact from every list.
+CREATE INDEX IF NOT EXISTS idx_contact_list_members_contact
+    ON contact_list_members (contact_id);
@@ -0,0 +1,15 @@
+-- 008_suppressions.sql
+-- Opt-out list: numbers that must not be messaged, globally or per sender ID.
+
+CREATE TABLE IF NOT EXISTS suppressions (
+    phone      TEXT        NOT NULL,
+    sender     TEXT        NOT NULL DEFAULT '', -- '' is the global list
+    reason     TEXT,
+    source     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (phone, sender)
+);
+
+-- Rows skipped at job time because the number was suppressed.
+ALTER TABLE broadcast_jobs
+    ADD COLUMN IF NOT EXISTS suppressed INTEGER NOT NULL DEFAULT 0;
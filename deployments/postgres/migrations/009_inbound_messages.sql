@@ -0,0 +1,24 @@
+-- 009_inbound_messages.sql
+-- Mobile-originated messages received from providers.
+
+CREATE TABLE IF NOT EXISTS inbound_messages (
+    id           UUID        PRIMARY KEY,
+    provider     TEXT        NOT NULL,
+    provider_id  TEXT        NOT NULL DEFAULT '',
+    from_number  TEXT        NOT NULL,
+    to_number    TEXT        NOT NULL,
+    body         TEXT        NOT NULL,
+    keyword      TEXT,
+    action       TEXT,
+    received_at  TIMESTAMPTZ NOT NULL,
+    processed_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL
+);
+
+-- Redelivered webhooks carry the same provider ID and are stored once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inbound_messages_provider_id
+    ON inbound_messages (provider, provider_id)
+    WHERE provider_id <> '';
+
+CREATE INDEX IF NOT EXISTS idx_inbound_messages_from
+    ON inbound_messages (from_number);
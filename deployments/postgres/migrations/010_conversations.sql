@@ -0,0 +1,11 @@
+-- 010_conversations.sql
+-- Link inbound messages to the outbound message they reply to, and index
+-- messages by recipient for conversation threads.
+
+ALTER TABLE inbound_messages ADD COLUMN IF NOT EXISTS message_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_inbound_messages_message
+    ON inbound_messages (message_id);
+
+CREATE INDEX IF NOT EXISTS idx_messages_to_created
+    ON messages (to_number, created_at);
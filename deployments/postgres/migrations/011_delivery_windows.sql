@@ -0,0 +1,19 @@
+-- 011_delivery_windows.sql
+-- Delivery windows (minutes after midnight, recipient local time) on
+-- broadcasts and jobs, and per-message time zones and publish times.
+
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS window_start INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS window_end   INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE broadcast_jobs
+    ADD COLUMN IF NOT EXISTS window_start INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS window_end   INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS time_zone  TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS not_before TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- The outbox publisher polls due pending messages in not_before order.
+CREATE INDEX IF NOT EXISTS idx_messages_status_not_before
+    ON messages (status, not_before);
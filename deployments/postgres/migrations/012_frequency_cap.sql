@@ -0,0 +1,10 @@
+-- 012_frequency_cap.sql
+-- Broadcast categories for frequency capping, and the per-job count of
+-- messages suppressed by the cap.
+
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS category TEXT NOT NULL DEFAULT 'marketing';
+
+ALTER TABLE broadcast_jobs
+    ADD COLUMN IF NOT EXISTS category TEXT    NOT NULL DEFAULT 'marketing',
+    ADD COLUMN IF NOT EXISTS capped   INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,13 @@
+-- 013_dedup_window.sql
+-- Content hashes for cross-broadcast duplicate detection, and the dedup
+-- window and duplicate count of broadcast jobs.
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS content_hash TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_messages_content_hash
+    ON messages (content_hash, created_at);
+
+ALTER TABLE broadcast_jobs
+    ADD COLUMN IF NOT EXISTS dedup_hours INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS duplicates  INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,21 @@
+-- 014_sender_ids.sql
+-- Registry of sender IDs with the countries each may send to, and the
+-- sender of broadcasts, their messages and broadcast jobs.
+
+CREATE TABLE IF NOT EXISTS sender_ids (
+    sender        TEXT PRIMARY KEY,
+    type          TEXT NOT NULL,
+    country_codes JSONB NOT NULL DEFAULT '[]',
+    description   TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL,
+    updated_at    TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS sender TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS sender TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE broadcast_jobs
+    ADD COLUMN IF NOT EXISTS sender TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,21 @@
+-- 015_otps.sql
+-- One-time passwords, and the queue lane of messages.
+
+CREATE TABLE IF NOT EXISTS otps (
+    id           UUID PRIMARY KEY,
+    phone        TEXT NOT NULL,
+    code_hash    TEXT NOT NULL,
+    message_id   UUID,
+    status       TEXT NOT NULL DEFAULT 'pending',
+    attempts     INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    verified_at  TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_otps_phone_created
+    ON otps (phone, created_at);
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'normal';
@@ -0,0 +1,32 @@
+-- 016_short_links.sql
+-- Per-recipient short links and their clicks, and the link shortening
+-- option of broadcasts and broadcast jobs.
+
+CREATE TABLE IF NOT EXISTS short_links (
+    code         TEXT PRIMARY KEY,
+    message_id   UUID NOT NULL,
+    broadcast_id UUID NOT NULL,
+    url          TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_short_links_message
+    ON short_links (message_id);
+
+CREATE TABLE IF NOT EXISTS link_clicks (
+    id           BIGSERIAL PRIMARY KEY,
+    code         TEXT NOT NULL,
+    message_id   UUID NOT NULL,
+    broadcast_id UUID NOT NULL,
+    user_agent   TEXT NOT NULL DEFAULT '',
+    clicked_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_link_clicks_broadcast
+    ON link_clicks (broadcast_id);
+
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS shorten_links BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE broadcast_jobs
+    ADD COLUMN IF NOT EXISTS shorten_links BOOLEAN NOT NULL DEFAULT FALSE;
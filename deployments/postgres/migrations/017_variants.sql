@@ -0,0 +1,12 @@
+-- 017_variants.sql
+-- A/B test variants of broadcasts and broadcast jobs, and the variant each
+-- message was assigned.
+
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS variants JSONB;
+
+ALTER TABLE broadcast_jobs
+    ADD COLUMN IF NOT EXISTS variants JSONB;
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS variant TEXT NOT NULL DEFAULT '';
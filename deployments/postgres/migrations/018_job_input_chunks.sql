@@ -0,0 +1,13 @@
+-- 018_job_input_chunks.sql
+-- Broadcast job input is stored in 1 MB chunks instead of a single BYTEA
+-- value, so uploads and imports never hold the whole file in memory.
+
+CREATE TABLE IF NOT EXISTS broadcast_job_chunks (
+    job_id UUID  NOT NULL,
+    seq    INT   NOT NULL,
+    data   BYTEA NOT NULL,
+    PRIMARY KEY (job_id, seq)
+);
+
+ALTER TABLE broadcast_jobs
+    DROP COLUMN IF EXISTS input;
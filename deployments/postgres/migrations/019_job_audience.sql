@@ -0,0 +1,7 @@
+-- 019_job_audience.sql
+-- Contact lists and segment a broadcast job targets besides its input's
+-- recipients, expanded when the job runs.
+
+ALTER TABLE broadcast_jobs
+    ADD COLUMN IF NOT EXISTS list_ids JSONB,
+    ADD COLUMN IF NOT EXISTS segment  TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,9 @@
+-- 020_idempotency_results.sql
+-- Idempotency keys store the full creation result, so a replay reports the
+-- same rejected, suppressed, capped and duplicate counts as the original.
+
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS rejected   INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS suppressed INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS capped     INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS duplicates INT NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- 021_message_provider.sql
+-- Messages record which provider sent them. Provider IDs are only unique per
+-- provider, so delivery receipts are matched on (provider, provider_id).
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS provider TEXT NOT NULL DEFAULT '';
+
+-- Every message sent so far went through the mock provider.
+UPDATE messages SET provider = 'mock' WHERE provider_id IS NOT NULL AND provider = '';
@@ -0,0 +1,10 @@
+-- 022_broadcast_counts.sql
+-- Broadcasts store the counts of their creation, so a job that runs again
+-- after its broadcast was committed reports the same totals.
+
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS queued     INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rejected   INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS suppressed INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS capped     INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS duplicates INT NOT NULL DEFAULT 0;
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// enqueueMessageCallbacks snapshots the current status of the selected
+// messages into callback_events. The dedup key makes each status fire once.
+const enqueueMessageCallbacks = `
+	INSERT INTO callback_events
+		(id, broadcast_id, message_id, event, url, payload, dedup_key, status, attempts, next_attempt_at, created_at, updated_at)
+	SELECT
+		gen_random_uuid(), m.broadcast_id, m.id, 'message.' || m.status, b.callback_url,
+		jsonb_build_object(
+			'event', 'message.' || m.status,
+			'broadcast_id', m.broadcast_id,
+			'message_id', m.id,
+			'to', m.to_number,
+			'status', m.status,
+			'provider_id', m.provider_id,
+			'error_code', NULLIF(m.error_code, ''),
+			'error_description', NULLIF(m.error_description, ''),
+			'delivered_at', m.delivered_at,
+			'occurred_at', NOW()
+		),
+		'message:' || m.id || ':' || m.status, 'pending', 0, NOW(), NOW(), NOW()
+	FROM messages AS m
+	JOIN broadcasts AS b ON b.id = m.broadcast_id
+	WHERE %s IN ? AND b.callback_url <> '' AND m.status IN ('sent', 'delivered', 'failed', 'suppressed')
+	ON CONFLICT (dedup_key) DO NOTHING`
+
+// enqueueCompletedCallbacks queues broadcast.completed for the broadcasts of
+// the selected messages once none of their messages is still in flight.
+const enqueueCompletedCallbacks = `
+	INSERT INTO callback_events
+		(id, broadcast_id, event, url, payload, dedup_key, status, attempts, next_attempt_at, created_at, updated_at)
+	SELECT
+		gen_random_uuid(), b.id, 'broadcast.completed', b.callback_url,
+		jsonb_build_object(
+			'event', 'broadcast.completed',
+			'broadcast_id', b.id,
+			'total', (SELECT COUNT(*) FROM messages WHERE broadcast_id = b.id),
+			'delivered', (SELECT COUNT(*) FROM messages WHERE broadcast_id = b.id AND status = 'delivered'),
+			'failed', (SELECT COUNT(*) FROM messages WHERE broadcast_id = b.id AND status = 'failed'),
+			'suppressed', (SELECT COUNT(*) FROM messages WHERE broadcast_id = b.id AND status = 'suppressed'),
+			'occurred_at', NOW()
+		),
+		'broadcast:' || b.id || ':completed', 'pending', 0, NOW(), NOW(), NOW()
+	FROM broadcasts AS b
+	WHERE b.callback_url <> ''
+		AND b.id IN (SELECT DISTINCT m.broadcast_id FROM messages AS m WHERE %s IN ?)
+		AND NOT EXISTS (
+			SELECT 1 FROM messages
+			WHERE broadcast_id = b.id AND status NOT IN ('delivered', 'failed', 'suppressed')
+		)
+	ON CONFLICT (dedup_key) DO NOTHING`
+
+// EnqueueStatusCallbacks queues status events for the given messages.
+func (r *Repository) EnqueueStatusCallbacks(ctx context.Context, messageIDs []uuid.UUID) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+	return r.enqueueCallbacks(ctx, "m.id", messageIDs)
+}
+
+// EnqueueStatusCallbacksByProviderRef queues status events for the given provider references.
+func (r *Repository) EnqueueStatusCallbacksByProviderRef(ctx context.Context, refs []domain.ProviderRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	return r.enqueueCallbacks(ctx, "(m.provider, m.provider_id)", refTuples(refs))
+}
+
+// enqueueCallbacks runs both inserts for messages selected by column IN
+// values. column is qualified with the messages alias m.
+func (r *Repository) enqueueCallbacks(ctx context.Context, column string, values interface{}) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf(enqueueMessageCallbacks, column), values).Error; err != nil {
+			return fmt.Errorf("enqueue message callbacks: %w", err)
+		}
+		if err := tx.Exec(fmt.Sprintf(enqueueCompletedCallbacks, column), values).Error; err != nil {
+			return fmt.Errorf("enqueue completed callbacks: %w", err)
+		}
+		return nil
+	})
+}
+
+// ClaimDueCallbacks leases up to limit due events. SKIP LOCKED lets several
+// dispatchers poll concurrently without handing out the same event twice.
+func (r *Repository) ClaimDueCallbacks(ctx context.Context, limit int, lease time.Duration) ([]domain.CallbackEvent, error) {
+	var events []domain.CallbackEvent
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE callback_events SET
+			status = ?,
+			next_attempt_at = ?,
+			updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM callback_events
+			WHERE status IN (?, ?) AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		domain.CallbackSending,
+		time.Now().UTC().Add(lease),
+		domain.CallbackPending, domain.CallbackSending,
+		limit,
+	).Scan(&events).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("claim due callbacks: %w", err)
+	}
+	return events, nil
+}
+
+// RecordCallbackAttempt appends to the delivery log and stores the event's new state.
+func (r *Repository) RecordCallbackAttempt(ctx context.Context, event domain.CallbackEvent, attempt domain.CallbackAttempt) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&attempt).Error; err != nil {
+			return fmt.Errorf("create callback attempt: %w", err)
+		}
+
+		err := tx.Model(&domain.CallbackEvent{}).
+			Where("id = ?", event.ID).
+			Updates(map[string]interface{}{
+				"status":          event.Status,
+				"attempts":        event.Attempts,
+				"next_attempt_at": event.NextAttemptAt.UTC(),
+				"last_error":      event.LastError,
+				"updated_at":      time.Now().UTC(),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("update callback event: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetCallback returns an event with its delivery log, oldest attempt first.
+func (r *Repository) GetCallback(ctx context.Context, id uuid.UUID) (*domain.CallbackEvent, []domain.CallbackAttempt, error) {
+	var event domain.CallbackEvent
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, domain.ErrCallbackNotFound
+		}
+		return nil, nil, fmt.Errorf("get callback: %w", err)
+	}
+
+	var attempts []domain.CallbackAttempt
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", id).
+		Order("attempted_at ASC").
+		Find(&attempts).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("get callback attempts: %w", err)
+	}
+
+	return &event, attempts, nil
+}
+
+// ListCallbacks returns the events of a broadcast, newest first.
+func (r *Repository) ListCallbacks(ctx context.Context, broadcastID uuid.UUID, limit int) ([]domain.CallbackEvent, error) {
+	var events []domain.CallbackEvent
+	err := r.db.WithContext(ctx).
+		Where("broadcast_id = ?", broadcastID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&events).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("list callbacks: %w", err)
+	}
+	return events, nil
+}
+
+// ResetCallback makes an event due immediately with a fresh attempt budget.
+func (r *Repository) ResetCallback(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.CallbackEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          domain.CallbackPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now().UTC(),
+			"updated_at":      time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("reset callback: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrCallbackNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,326 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// listColumns selects a contact list with its member count.
+const listColumns = `contact_lists.*,
+	(SELECT COUNT(*) FROM contact_list_members WHERE list_id = contact_lists.id) AS members`
+
+// SaveContact inserts a new contact.
+func (r *Repository) SaveContact(ctx context.Context, c domain.Contact) error {
+	if err := r.db.WithContext(ctx).Create(&c).Error; err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrContactExists
+		}
+		return fmt.Errorf("create contact: %w", err)
+	}
+	return nil
+}
+
+// UpdateContact replaces a contact's phone and attributes.
+func (r *Repository) UpdateContact(ctx context.Context, c domain.Contact) error {
+	attributes, err := json.Marshal(c.Attributes)
+	if err != nil {
+		return fmt.Errorf("marshal attributes: %w", err)
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&domain.Contact{}).
+		Where("id = ?", c.ID).
+		Updates(map[string]interface{}{
+			"phone":      c.Phone,
+			"attributes": string(attributes),
+			"updated_at": time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return domain.ErrContactExists
+		}
+		return fmt.Errorf("update contact: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrContactNotFound
+	}
+
+	return nil
+}
+
+// GetContact retrieves a contact by ID.
+func (r *Repository) GetContact(ctx context.Context, id uuid.UUID) (*domain.Contact, error) {
+	var c domain.Contact
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&c).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrContactNotFound
+		}
+		return nil, fmt.Errorf("get contact: %w", err)
+	}
+	return &c, nil
+}
+
+// ListContacts returns a page of contacts ordered by phone.
+func (r *Repository) ListContacts(ctx context.Context, filter ports.ContactFilter) ([]domain.Contact, error) {
+	q := r.db.WithContext(ctx).Model(&domain.Contact{})
+	if filter.ListID != nil {
+		q = q.Where("id IN (SELECT contact_id FROM contact_list_members WHERE list_id = ?)", *filter.ListID)
+	}
+
+	var contacts []domain.Contact
+	err := q.Order("phone ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&contacts).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("list contacts: %w", err)
+	}
+	return contacts, nil
+}
+
+// DeleteContact removes a contact; memberships cascade.
+func (r *Repository) DeleteContact(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.Contact{})
+	if result.Error != nil {
+		return fmt.Errorf("delete contact: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrContactNotFound
+	}
+	return nil
+}
+
+// UpsertContacts inserts contacts or merges their attributes into the
+// existing contact with the same phone, in one statement.
+func (r *Repository) UpsertContacts(ctx context.Context, contacts []domain.Contact) ([]uuid.UUID, error) {
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, 0, len(contacts))
+	args := make([]interface{}, 0, len(contacts)*4)
+	for i, c := range contacts {
+		attributes, err := json.Marshal(c.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("marshal attributes: %w", err)
+		}
+		values = append(values, "(?::int, ?::uuid, ?, ?::jsonb)")
+		args = append(args, i, uuid.New(), c.Phone, string(attributes))
+	}
+
+	// ord keeps the returned IDs in input order.
+	query := fmt.Sprintf(`
+		WITH input (ord, id, phone, attributes) AS (VALUES %s),
+		upserted AS (
+			INSERT INTO contacts (id, phone, attributes, created_at, updated_at)
+			SELECT id, phone, attributes, NOW(), NOW() FROM input
+			ON CONFLICT (phone) DO UPDATE SET
+				attributes = contacts.attributes || EXCLUDED.attributes,
+				updated_at = EXCLUDED.updated_at
+			RETURNING id, phone
+		)
+		SELECT upserted.id FROM upserted
+		JOIN input ON input.phone = upserted.phone
+		ORDER BY input.ord`, strings.Join(values, ", "))
+
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&ids).Error; err != nil {
+		return nil, fmt.Errorf("upsert contacts: %w", err)
+	}
+	return ids, nil
+}
+
+// SaveList inserts a new contact list.
+func (r *Repository) SaveList(ctx context.Context, l domain.ContactList) error {
+	if err := r.db.WithContext(ctx).Omit("Members").Create(&l).Error; err != nil {
+		return fmt.Errorf("create list: %w", err)
+	}
+	return nil
+}
+
+// UpdateList replaces a list's name and description.
+func (r *Repository) UpdateList(ctx context.Context, l domain.ContactList) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.ContactList{}).
+		Where("id = ?", l.ID).
+		Updates(map[string]interface{}{
+			"name":        l.Name,
+			"description": l.Description,
+			"updated_at":  time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("update list: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrListNotFound
+	}
+
+	return nil
+}
+
+// GetList retrieves a list by ID with its member count.
+func (r *Repository) GetList(ctx context.Context, id uuid.UUID) (*domain.ContactList, error) {
+	var l domain.ContactList
+	err := r.db.WithContext(ctx).
+		Select(listColumns).
+		Where("id = ?", id).
+		First(&l).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrListNotFound
+		}
+		return nil, fmt.Errorf("get list: %w", err)
+	}
+	return &l, nil
+}
+
+// ListLists returns all lists with their member counts, by name.
+func (r *Repository) ListLists(ctx context.Context) ([]domain.ContactList, error) {
+	var lists []domain.ContactList
+	err := r.db.WithContext(ctx).
+		Select(listColumns).
+		Order("name ASC").
+		Find(&lists).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("list lists: %w", err)
+	}
+	return lists, nil
+}
+
+// DeleteList removes a list; memberships cascade, contacts are kept.
+func (r *Repository) DeleteList(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.ContactList{})
+	if result.Error != nil {
+		return fmt.Errorf("delete list: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrListNotFound
+	}
+	return nil
+}
+
+// AddListMembers adds contacts to a list, ignoring existing members and
+// unknown contact IDs.
+func (r *Repository) AddListMembers(ctx context.Context, listID uuid.UUID, contactIDs []uuid.UUID) error {
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Exec(`
+		INSERT INTO contact_list_members (list_id, contact_id, created_at)
+		SELECT ?, id, NOW() FROM contacts WHERE id IN ?
+		ON CONFLICT DO NOTHING`,
+		listID, contactIDs,
+	).Error
+
+	if err != nil {
+		return fmt.Errorf("add list members: %w", err)
+	}
+	return nil
+}
+
+// RemoveListMembers removes contacts from a list.
+func (r *Repository) RemoveListMembers(ctx context.Context, listID uuid.UUID, contactIDs []uuid.UUID) error {
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("list_id = ? AND contact_id IN ?", listID, contactIDs).
+		Delete(&domain.ContactListMember{}).Error
+
+	if err != nil {
+		return fmt.Errorf("remove list members: %w", err)
+	}
+	return nil
+}
+
+// AudienceContacts returns the distinct contacts in the audience, by phone.
+func (r *Repository) AudienceContacts(ctx context.Context, audience ports.Audience, limit int) ([]domain.Contact, error) {
+	q, err := r.audienceQuery(ctx, audience)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	var contacts []domain.Contact
+	if err := q.Order("phone ASC").Find(&contacts).Error; err != nil {
+		return nil, fmt.Errorf("audience contacts: %w", err)
+	}
+	return contacts, nil
+}
+
+// CountAudience returns the number of distinct contacts in the audience.
+func (r *Repository) CountAudience(ctx context.Context, audience ports.Audience) (int64, error) {
+	q, err := r.audienceQuery(ctx, audience)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	if err := q.Count(&n).Error; err != nil {
+		return 0, fmt.Errorf("count audience: %w", err)
+	}
+	return n, nil
+}
+
+// audienceQuery checks that the audience's lists exist and scopes a contacts
+// query to their members and the segment.
+func (r *Repository) audienceQuery(ctx context.Context, audience ports.Audience) (*gorm.DB, error) {
+	q := r.db.WithContext(ctx).Model(&domain.Contact{})
+
+	if len(audience.ListIDs) > 0 {
+		var found int64
+		if err := r.db.WithContext(ctx).Model(&domain.ContactList{}).Where("id IN ?", audience.ListIDs).Count(&found).Error; err != nil {
+			return nil, fmt.Errorf("count lists: %w", err)
+		}
+		if int(found) != len(uniqueIDs(audience.ListIDs)) {
+			return nil, domain.ErrListNotFound
+		}
+		q = q.Where("contacts.id IN (SELECT contact_id FROM contact_list_members WHERE list_id IN ?)", audience.ListIDs)
+	}
+
+	if audience.Segment != nil {
+		where, args, err := segmentSQL(audience.Segment)
+		if err != nil {
+			return nil, err
+		}
+		q = q.Where(where, args...)
+	}
+
+	return q, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique_violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
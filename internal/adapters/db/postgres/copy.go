@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// copyThreshold is the batch size from which SaveMessages switches from
+// multi-row INSERTs to COPY. Below it the extra round trip to grab a raw
+// connection costs more than it saves.
+const copyThreshold = 1000
+
+// messageColumns are the messages columns written by COPY, in row order.
+var messageColumns = []string{
+	"id", "broadcast_id", "to_number", "sender", "body", "status", "provider_id",
+	"created_at", "updated_at", "error_code", "error_description", "delivered_at",
+	"time_zone", "not_before", "content_hash", "priority", "variant",
+}
+
+// InsertMessages inserts messages with GORM multi-row INSERTs of 100 rows.
+func (r *Repository) InsertMessages(ctx context.Context, msgs []domain.Message) error {
+	if err := r.db.WithContext(ctx).CreateInBatches(msgs, 100).Error; err != nil {
+		return fmt.Errorf("create messages: %w", err)
+	}
+	return nil
+}
+
+// CopyMessages streams messages into the table with COPY FROM STDIN.
+// COPY is all-or-nothing on its own; inside WithinTx it joins the transaction.
+func (r *Repository) CopyMessages(ctx context.Context, msgs []domain.Message) error {
+	conn := r.conn
+	if conn == nil {
+		sqlDB, err := r.db.DB()
+		if err != nil {
+			return fmt.Errorf("get sql.DB from gorm: %w", err)
+		}
+		if conn, err = sqlDB.Conn(ctx); err != nil {
+			return fmt.Errorf("acquire connection: %w", err)
+		}
+		defer conn.Close()
+	}
+
+	return conn.Raw(func(driverConn any) error {
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("copy messages: unexpected driver connection %T", driverConn)
+		}
+
+		n, err := pgxConn.Conn().CopyFrom(ctx, pgx.Identifier{"messages"}, messageColumns, messageRows(msgs))
+		if err != nil {
+			return fmt.Errorf("copy messages: %w", err)
+		}
+		if int(n) != len(msgs) {
+			return fmt.Errorf("copy messages: wrote %d of %d rows", n, len(msgs))
+		}
+		return nil
+	})
+}
+
+// messageRows feeds COPY one row at a time, so no second copy of a large
+// recipient list is built in memory.
+func messageRows(msgs []domain.Message) pgx.CopyFromSource {
+	return pgx.CopyFromSlice(len(msgs), func(i int) ([]any, error) {
+		m := msgs[i]
+		return []any{
+			[16]byte(m.ID), [16]byte(m.BroadcastID), m.To, m.Sender, m.Body, string(m.Status), m.ProviderID,
+			m.CreatedAt, m.UpdatedAt, m.ErrorCode, m.ErrorDescription, m.DeliveredAt,
+			m.TimeZone, m.NotBefore, m.ContentHash, string(m.Priority), m.Variant,
+		}, nil
+	})
+}
+
+// beginTx starts a transaction on a dedicated connection and returns a
+// Repository bound to it. Holding the *sql.Conn lets CopyMessages reach the
+// underlying pgx connection, which GORM's own Transaction does not expose.
+func (r *Repository) beginTx(ctx context.Context) (*Repository, *sql.Tx, func(), error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("get sql.DB from gorm: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	db := r.db.WithContext(ctx).Session(&gorm.Session{})
+	db.Statement.ConnPool = tx
+
+	return &Repository{db: db, conn: conn}, tx, func() { conn.Close() }, nil
+}
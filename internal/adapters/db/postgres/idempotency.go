@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+)
+
+// ClaimIdempotencyKey inserts the key, taking over an expired one with the
+// same name. Concurrent claims are serialised by the primary key: inside a
+// transaction a second claim waits until the first commits or rolls back, so
+// exactly one request wins and the others see its stored outcome.
+func (r *Repository) ClaimIdempotencyKey(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO idempotency_keys (key, fingerprint, broadcast_id, queued, rejected, suppressed, capped, duplicates, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			fingerprint  = EXCLUDED.fingerprint,
+			broadcast_id = EXCLUDED.broadcast_id,
+			queued       = EXCLUDED.queued,
+			rejected     = EXCLUDED.rejected,
+			suppressed   = EXCLUDED.suppressed,
+			capped       = EXCLUDED.capped,
+			duplicates   = EXCLUDED.duplicates,
+			created_at   = EXCLUDED.created_at,
+			expires_at   = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`,
+		key.Key, key.Fingerprint, key.BroadcastID, key.Queued, key.Rejected, key.Suppressed, key.Capped, key.Duplicates,
+		key.CreatedAt.UTC(), key.ExpiresAt.UTC(),
+	)
+	if result.Error != nil {
+		return nil, fmt.Errorf("claim idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing domain.IdempotencyKey
+	if err := r.db.WithContext(ctx).Where("key = ?", key.Key).First(&existing).Error; err != nil {
+		return nil, fmt.Errorf("get idempotency key: %w", err)
+	}
+	return &existing, nil
+}
+
+// SaveIdempotencyResult records the counts of the broadcast created under a
+// claimed key, for replays to return.
+func (r *Repository) SaveIdempotencyResult(ctx context.Context, key domain.IdempotencyKey) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.IdempotencyKey{}).
+		Where("key = ?", key.Key).
+		Updates(map[string]interface{}{
+			"queued":     key.Queued,
+			"rejected":   key.Rejected,
+			"suppressed": key.Suppressed,
+			"capped":     key.Capped,
+			"duplicates": key.Duplicates,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("save idempotency result: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("idempotency key not found: %s", key.Key)
+	}
+	return nil
+}
+
+// PurgeExpiredIdempotencyKeys deletes keys that expired before now.
+func (r *Repository) PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", now.UTC()).
+		Delete(&domain.IdempotencyKey{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("purge idempotency keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveInbound stores a new inbound message, or returns the row already
+// stored under the same provider ID.
+func (r *Repository) SaveInbound(ctx context.Context, msg domain.InboundMessage) (*domain.InboundMessage, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "provider"}, {Name: "provider_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "provider_id <> ''"}}},
+			DoNothing:   true,
+		}).
+		Create(&msg)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("create inbound message: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return &msg, nil
+	}
+
+	var existing domain.InboundMessage
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND provider_id = ?", msg.Provider, msg.ProviderID).
+		First(&existing).Error
+	if err != nil {
+		return nil, fmt.Errorf("get inbound message: %w", err)
+	}
+	return &existing, nil
+}
+
+// MarkInboundProcessed records the keyword outcome of an inbound message.
+func (r *Repository) MarkInboundProcessed(ctx context.Context, id uuid.UUID, keyword string, action domain.KeywordAction) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.InboundMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"keyword":      keyword,
+			"action":       action,
+			"processed_at": time.Now().UTC(),
+		}).Error
+
+	if err != nil {
+		return fmt.Errorf("mark inbound processed: %w", err)
+	}
+	return nil
+}
+
+// LastOutboundMessage returns the newest message to phone that was handed to
+// a provider before the given time.
+func (r *Repository) LastOutboundMessage(ctx context.Context, phone string, before time.Time) (*domain.Message, error) {
+	var msg domain.Message
+	err := r.db.WithContext(ctx).
+		Where("to_number = ? AND created_at < ? AND provider_id <> ''", phone, before).
+		Order("created_at DESC").
+		First(&msg).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("get last outbound message: %w", err)
+	}
+	return &msg, nil
+}
+
+// conversationSQL merges both directions of a thread. Outbound messages are
+// placed at their creation time, inbound ones at the time they were received.
+const conversationSQL = `
+SELECT * FROM (
+	SELECT id, 'outbound' AS direction, body, status, broadcast_id,
+	       NULL::uuid AS in_reply_to, '' AS keyword, created_at AS at
+	FROM messages
+	WHERE to_number = @phone AND created_at < @before
+	UNION ALL
+	SELECT id, 'inbound' AS direction, body, '' AS status, NULL::uuid AS broadcast_id,
+	       message_id AS in_reply_to, COALESCE(keyword, '') AS keyword, received_at AS at
+	FROM inbound_messages
+	WHERE from_number = @phone AND received_at < @before
+) thread
+ORDER BY at DESC, id
+LIMIT @limit`
+
+// Conversation returns up to limit messages exchanged with phone before the
+// given time, newest first.
+func (r *Repository) Conversation(ctx context.Context, phone string, before time.Time, limit int) ([]domain.ConversationEntry, error) {
+	var entries []domain.ConversationEntry
+	err := r.db.WithContext(ctx).
+		Raw(conversationSQL, sql.Named("phone", phone), sql.Named("before", before), sql.Named("limit", limit)).
+		Scan(&entries).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("get conversation: %w", err)
+	}
+	return entries, nil
+}
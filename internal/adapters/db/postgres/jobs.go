@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SaveJob inserts a new queued job and its input, split into chunks of
+// domain.JobChunkSize, in one transaction.
+func (r *Repository) SaveJob(ctx context.Context, job domain.BroadcastJob, input io.Reader) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return fmt.Errorf("create job: %w", err)
+		}
+
+		buf := make([]byte, domain.JobChunkSize)
+		for seq := 0; ; seq++ {
+			n, err := io.ReadFull(input, buf)
+			if n > 0 {
+				chunk := domain.BroadcastJobChunk{JobID: job.ID, Seq: seq, Data: buf[:n]}
+				if err := tx.Create(&chunk).Error; err != nil {
+					return fmt.Errorf("create job chunk: %w", err)
+				}
+			}
+
+			switch {
+			case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+				return nil
+			case err != nil:
+				return fmt.Errorf("read job input: %w", err)
+			}
+		}
+	})
+}
+
+// JobInput returns a reader over a job's input that loads one chunk at a time.
+func (r *Repository) JobInput(ctx context.Context, id uuid.UUID) io.Reader {
+	return &jobInputReader{db: r.db.WithContext(ctx), jobID: id}
+}
+
+// jobInputReader reads the chunks of a job's input in order.
+type jobInputReader struct {
+	db    *gorm.DB
+	jobID uuid.UUID
+	seq   int
+	buf   []byte
+}
+
+func (r *jobInputReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		var chunks []domain.BroadcastJobChunk
+		err := r.db.
+			Where("job_id = ? AND seq = ?", r.jobID, r.seq).
+			Limit(1).
+			Find(&chunks).Error
+
+		if err != nil {
+			return 0, fmt.Errorf("get job chunk: %w", err)
+		}
+		if len(chunks) == 0 {
+			return 0, io.EOF
+		}
+		r.buf = chunks[0].Data
+		r.seq++
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// ClaimJob marks the oldest due job as running and clears the counts of any
+// earlier run. SKIP LOCKED lets several workers poll concurrently without
+// picking the same job.
+func (r *Repository) ClaimJob(ctx context.Context, staleAfter time.Duration) (*domain.BroadcastJob, error) {
+	now := time.Now().UTC()
+
+	var jobs []domain.BroadcastJob
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE broadcast_jobs SET
+			status = ?,
+			queued = 0,
+			rejected = 0,
+			suppressed = 0,
+			capped = 0,
+			duplicates = 0,
+			errors = '[]',
+			started_at = ?,
+			updated_at = ?
+		WHERE id = (
+			SELECT id FROM broadcast_jobs
+			WHERE status = ? OR (status = ? AND updated_at < ?)
+			ORDER BY created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		domain.JobRunning, now, now,
+		domain.JobQueued, domain.JobRunning, now.Add(-staleAfter),
+	).Scan(&jobs).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("claim job: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+// UpdateJobProgress stores the running totals of a job. Touching updated_at
+// also tells other workers the job is still alive.
+func (r *Repository) UpdateJobProgress(ctx context.Context, job domain.BroadcastJob) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.BroadcastJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"queued":     job.Queued,
+			"rejected":   job.Rejected,
+			"suppressed": job.Suppressed,
+			"capped":     job.Capped,
+			"duplicates": job.Duplicates,
+			"updated_at": time.Now().UTC(),
+		}).Error
+
+	if err != nil {
+		return fmt.Errorf("update job progress: %w", err)
+	}
+	return nil
+}
+
+// TouchJob updates a job's updated_at so other workers do not claim it as stale.
+func (r *Repository) TouchJob(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.BroadcastJob{}).
+		Where("id = ?", id).
+		Update("updated_at", time.Now().UTC()).Error
+
+	if err != nil {
+		return fmt.Errorf("touch job: %w", err)
+	}
+	return nil
+}
+
+// FinishJob stores the final state of a job and deletes its input.
+func (r *Repository) FinishJob(ctx context.Context, job domain.BroadcastJob) error {
+	now := time.Now().UTC()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", job.ID).Delete(&domain.BroadcastJobChunk{}).Error; err != nil {
+			return err
+		}
+
+		return tx.
+			Model(&domain.BroadcastJob{}).
+			Where("id = ?", job.ID).
+			Updates(map[string]interface{}{
+				"status":       job.Status,
+				"queued":       job.Queued,
+				"rejected":     job.Rejected,
+				"suppressed":   job.Suppressed,
+				"capped":       job.Capped,
+				"duplicates":   job.Duplicates,
+				"errors":       job.Errors,
+				"error":        job.Error,
+				"broadcast_id": job.BroadcastID,
+				"finished_at":  now,
+				"updated_at":   now,
+			}).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("finish job: %w", err)
+	}
+	return nil
+}
+
+// GetJob retrieves a job.
+func (r *Repository) GetJob(ctx context.Context, id uuid.UUID) (*domain.BroadcastJob, error) {
+	var job domain.BroadcastJob
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&job).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrJobNotFound
+		}
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	return &job, nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SaveShortLinks inserts the short links rewritten into message bodies.
+func (r *Repository) SaveShortLinks(ctx context.Context, links []domain.ShortLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).CreateInBatches(links, 1000).Error; err != nil {
+		return fmt.Errorf("create short links: %w", err)
+	}
+	return nil
+}
+
+// GetShortLink returns the link for a code.
+func (r *Repository) GetShortLink(ctx context.Context, code string) (domain.ShortLink, error) {
+	var link domain.ShortLink
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ShortLink{}, domain.ErrLinkNotFound
+		}
+		return domain.ShortLink{}, fmt.Errorf("get short link: %w", err)
+	}
+	return link, nil
+}
+
+// RecordClick stores one visit to a short link.
+func (r *Repository) RecordClick(ctx context.Context, click domain.LinkClick) error {
+	if err := r.db.WithContext(ctx).Create(&click).Error; err != nil {
+		return fmt.Errorf("record click: %w", err)
+	}
+	return nil
+}
+
+// FindBroadcast retrieves a broadcast by ID without its messages.
+func (r *Repository) FindBroadcast(ctx context.Context, id uuid.UUID) (*domain.Broadcast, error) {
+	var broadcast domain.Broadcast
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&broadcast).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrBroadcastNotFound
+		}
+		return nil, fmt.Errorf("find broadcast: %w", err)
+	}
+	return &broadcast, nil
+}
+
+// BroadcastStats counts a broadcast's messages by status and the clicks on
+// its short links, per A/B test variant. Clicks take their variant from the
+// clicked message.
+func (r *Repository) BroadcastStats(ctx context.Context, id uuid.UUID) (map[string]domain.BroadcastStats, error) {
+	stats := make(map[string]domain.BroadcastStats)
+
+	var rows []struct {
+		Variant string
+		Status  domain.Status
+		Count   int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.Message{}).
+		Select("variant, status, COUNT(*) AS count").
+		Where("broadcast_id = ?", id).
+		Group("variant, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("count messages by status: %w", err)
+	}
+	for _, row := range rows {
+		s := stats[row.Variant]
+		s.Add(row.Status, row.Count)
+		stats[row.Variant] = s
+	}
+
+	var clicks []struct {
+		Variant         string
+		Clicks          int
+		ClickedMessages int
+	}
+	err = r.db.WithContext(ctx).
+		Table("link_clicks AS c").
+		Select("m.variant, COUNT(*) AS clicks, COUNT(DISTINCT c.message_id) AS clicked_messages").
+		Joins("JOIN messages AS m ON m.id = c.message_id").
+		Where("c.broadcast_id = ?", id).
+		Group("m.variant").
+		Scan(&clicks).Error
+	if err != nil {
+		return nil, fmt.Errorf("count link clicks: %w", err)
+	}
+	for _, row := range clicks {
+		s := stats[row.Variant]
+		s.Clicks, s.ClickedMessages = row.Clicks, row.ClickedMessages
+		stats[row.Variant] = s
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SaveOTP stores a new OTP unless the number has one created after since.
+// A transaction-scoped advisory lock on the number makes the check and the
+// insert atomic across concurrent requests.
+func (r *Repository) SaveOTP(ctx context.Context, otp domain.OTP, since time.Time) (bool, error) {
+	saved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "otp:"+otp.Phone).Error; err != nil {
+			return fmt.Errorf("lock otp phone: %w", err)
+		}
+
+		var recent int64
+		err := tx.Model(&domain.OTP{}).
+			Where("phone = ? AND created_at > ?", otp.Phone, since.UTC()).
+			Count(&recent).Error
+		if err != nil {
+			return fmt.Errorf("count recent otps: %w", err)
+		}
+		if recent > 0 {
+			return nil
+		}
+
+		if err := tx.Create(&otp).Error; err != nil {
+			return fmt.Errorf("create otp: %w", err)
+		}
+		saved = true
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+	return saved, nil
+}
+
+// AttachOTPMessage records the OTP's message and retires the number's
+// older pending OTPs, so only the newest code verifies.
+func (r *Repository) AttachOTPMessage(ctx context.Context, id, messageID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			UPDATE otps SET status = ?
+			WHERE phone = (SELECT phone FROM otps WHERE id = ?) AND id <> ? AND status = ?`,
+			domain.OTPReplaced, id, id, domain.OTPPending,
+		).Error
+		if err != nil {
+			return fmt.Errorf("replace otps: %w", err)
+		}
+
+		result := tx.Model(&domain.OTP{}).Where("id = ?", id).Update("message_id", messageID)
+		if result.Error != nil {
+			return fmt.Errorf("attach otp message: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrOTPNotFound
+		}
+		return nil
+	})
+}
+
+// GetOTP retrieves an OTP by ID.
+func (r *Repository) GetOTP(ctx context.Context, id uuid.UUID) (domain.OTP, error) {
+	var otp domain.OTP
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&otp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.OTP{}, domain.ErrOTPNotFound
+		}
+		return domain.OTP{}, fmt.Errorf("get otp: %w", err)
+	}
+	return otp, nil
+}
+
+// RecordOTPAttempt counts one verification attempt in a single statement,
+// so concurrent guesses can never exceed the OTP's attempt limit.
+func (r *Repository) RecordOTPAttempt(ctx context.Context, id uuid.UUID, now time.Time) (int, bool, error) {
+	var attempts []int
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE otps SET attempts = attempts + 1
+		WHERE id = ? AND status = ? AND expires_at > ? AND attempts < max_attempts
+		RETURNING attempts`,
+		id, domain.OTPPending, now,
+	).Scan(&attempts).Error
+
+	if err != nil {
+		return 0, false, fmt.Errorf("record otp attempt: %w", err)
+	}
+	if len(attempts) == 0 {
+		return 0, false, nil
+	}
+	return attempts[0], true, nil
+}
+
+// FinishOTP moves a pending OTP to a final status.
+func (r *Repository) FinishOTP(ctx context.Context, id uuid.UUID, status domain.OTPStatus, at time.Time) (bool, error) {
+	updates := map[string]interface{}{"status": status}
+	if status == domain.OTPVerified {
+		updates["verified_at"] = at
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&domain.OTP{}).
+		Where("id = ? AND status = ?", id, domain.OTPPending).
+		Updates(updates)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("finish otp: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FailedOTPAttempts counts wrong codes for phone on OTPs created after since.
+// Every attempt on an OTP is wrong except the one that verified it.
+func (r *Repository) FailedOTPAttempts(ctx context.Context, phone string, since time.Time) (int, error) {
+	var failed int
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(attempts - CASE WHEN status = ? THEN 1 ELSE 0 END), 0)
+		FROM otps
+		WHERE phone = ? AND created_at > ?`,
+		domain.OTPVerified, phone, since,
+	).Scan(&failed).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("count failed otp attempts: %w", err)
+	}
+	return failed, nil
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+)
+
+// receiptUpdate applies the receipt row "v" to message "m". A suppressed
+// message was never sent and keeps its state; a non-final receipt (e.g.
+// "sent") never overwrites a delivered or failed message, so duplicate and
+// out-of-order receipts are safe.
+const receiptUpdate = `
+	UPDATE messages AS m SET
+		status = CASE
+			WHEN m.status = 'suppressed' THEN m.status
+			WHEN m.status IN ('delivered', 'failed') AND v.status NOT IN ('delivered', 'failed', 'suppressed') THEN m.status
+			ELSE v.status
+		END,
+		error_code = CASE
+			WHEN m.status = 'suppressed' THEN m.error_code
+			ELSE COALESCE(NULLIF(v.error_code, ''), m.error_code)
+		END,
+		error_description = CASE
+			WHEN m.status = 'suppressed' THEN m.error_description
+			ELSE COALESCE(NULLIF(v.error_description, ''), m.error_description)
+		END,
+		delivered_at = CASE
+			WHEN m.status = 'suppressed' THEN m.delivered_at
+			ELSE COALESCE(v.delivered_at, m.delivered_at)
+		END,
+		updated_at = NOW()`
+
+// ApplyReceipts records a batch of delivery receipts in a single UPDATE,
+// matching messages by provider and provider ID. It returns the receipts that
+// matched no message.
+func (r *Repository) ApplyReceipts(ctx context.Context, receipts []domain.Receipt) ([]domain.Receipt, error) {
+	if len(receipts) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, 0, len(receipts))
+	args := make([]interface{}, 0, len(receipts)*6)
+	for _, rc := range receipts {
+		values = append(values, "(?::text, ?::text, ?::text, ?::text, ?::text, ?::timestamptz)")
+		args = append(args, rc.Provider, rc.ProviderID, rc.Status, rc.ErrorCode, rc.ErrorDescription, rc.DeliveredAt)
+	}
+
+	query := receiptUpdate + `
+		FROM (VALUES ` + strings.Join(values, ", ") + `) AS v(provider, provider_id, status, error_code, error_description, delivered_at)
+		WHERE m.provider = v.provider AND m.provider_id = v.provider_id
+		RETURNING m.provider, m.provider_id`
+
+	var matched []domain.ProviderRef
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&matched).Error; err != nil {
+		return nil, fmt.Errorf("apply receipts: %w", err)
+	}
+
+	found := make(map[domain.ProviderRef]bool, len(matched))
+	for _, ref := range matched {
+		found[ref] = true
+	}
+
+	var unmatched []domain.Receipt
+	for _, rc := range receipts {
+		if !found[rc.Ref()] {
+			unmatched = append(unmatched, rc)
+		}
+	}
+	return unmatched, nil
+}
+
+// ParkReceipts stores receipts that matched no message in pending_dlrs until expiresAt.
+func (r *Repository) ParkReceipts(ctx context.Context, receipts []domain.Receipt, expiresAt time.Time) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	rows := make([]domain.PendingDLR, 0, len(receipts))
+	for _, rc := range receipts {
+		rows = append(rows, domain.PendingDLR{
+			Provider:         rc.Provider,
+			ProviderID:       rc.ProviderID,
+			Status:           rc.Status,
+			ErrorCode:        rc.ErrorCode,
+			ErrorDescription: rc.ErrorDescription,
+			DeliveredAt:      rc.DeliveredAt,
+			ReceivedAt:       now,
+			ExpiresAt:        expiresAt.UTC(),
+		})
+	}
+
+	if err := r.db.WithContext(ctx).CreateInBatches(rows, 100).Error; err != nil {
+		return fmt.Errorf("park receipts: %w", err)
+	}
+	return nil
+}
+
+// ReplayPendingReceipts claims (deletes) parked receipts whose provider
+// reference is now attached to a message and applies the most relevant one per
+// message: the latest final receipt if any, otherwise the latest receipt.
+func (r *Repository) ReplayPendingReceipts(ctx context.Context, refs []domain.ProviderRef) (int64, error) {
+	if len(refs) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		WITH claimed AS (
+			DELETE FROM pending_dlrs AS p
+			USING messages AS pm
+			WHERE p.provider = pm.provider AND p.provider_id = pm.provider_id
+				AND (p.provider, p.provider_id) IN ?
+			RETURNING p.provider, p.provider_id, p.status, p.error_code, p.error_description, p.delivered_at, p.received_at
+		), v AS (
+			SELECT DISTINCT ON (provider, provider_id) *
+			FROM claimed
+			ORDER BY provider, provider_id, (status IN ('delivered', 'failed', 'suppressed')) DESC, received_at DESC
+		)` + receiptUpdate + `
+		FROM v
+		WHERE m.provider = v.provider AND m.provider_id = v.provider_id`
+
+	result := r.db.WithContext(ctx).Exec(query, refTuples(refs))
+	if result.Error != nil {
+		return 0, fmt.Errorf("replay pending receipts: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// refTuples converts refs into (provider, provider_id) rows for a tuple IN clause.
+func refTuples(refs []domain.ProviderRef) [][]interface{} {
+	tuples := make([][]interface{}, 0, len(refs))
+	for _, ref := range refs {
+		tuples = append(tuples, []interface{}{ref.Provider, ref.ProviderID})
+	}
+	return tuples
+}
+
+// PurgeExpiredReceipts deletes parked receipts that expired before now.
+func (r *Repository) PurgeExpiredReceipts(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", now.UTC()).
+		Delete(&domain.PendingDLR{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("purge expired receipts: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -2,20 +2,28 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
 
 	"github.com/google/uuid"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
 // Repository implements ports.MessageRepository using PostgreSQL with GORM.
 type Repository struct {
 	db *gorm.DB
+
+	// conn is the connection holding the open transaction when the Repository
+	// was handed out by WithinTx; nil otherwise.
+	conn *sql.Conn
 }
 
 // New opens a PostgreSQL connection using GORM and runs auto-migration.
@@ -47,9 +55,15 @@ func New(dsn string) (*Repository, error) {
 
 	// Auto-migrate schemas
 	fmt.Println("🔄 Running GORM auto-migration...")
-	if err := db.AutoMigrate(&domain.Broadcast{}, &domain.Message{}); err != nil {
+	if err := db.AutoMigrate(&domain.Broadcast{}, &domain.Message{}, &domain.PendingDLR{}, &domain.CallbackEvent{}, &domain.CallbackAttempt{}, &domain.IdempotencyKey{}, &domain.BroadcastJob{}, &domain.BroadcastJobChunk{}, &domain.Contact{}, &domain.ContactList{}, &domain.ContactListMember{}, &domain.Suppression{}, &domain.InboundMessage{}, &domain.SenderID{}, &domain.OTP{}, &domain.ShortLink{}, &domain.LinkClick{}); err != nil {
 		return nil, fmt.Errorf("auto-migrate: %w", err)
 	}
+	// Job input moved to broadcast_job_chunks; see 018_job_input_chunks.sql
+	if db.Migrator().HasColumn("broadcast_jobs", "input") {
+		if err := db.Migrator().DropColumn("broadcast_jobs", "input"); err != nil {
+			return nil, fmt.Errorf("drop broadcast_jobs.input: %w", err)
+		}
+	}
 	fmt.Println("✅ Auto-migration complete")
 
 	return &Repository{db: db}, nil
@@ -64,6 +78,40 @@ func (r *Repository) Close() error {
 	return sqlDB.Close()
 }
 
+// WithinTx runs fn against a Repository bound to a single transaction.
+// Nested transactions inside the repository's own methods become savepoints.
+func (r *Repository) WithinTx(ctx context.Context, fn func(repo ports.MessageRepository) error) (err error) {
+	if r.conn != nil {
+		// Already inside a transaction; fn simply joins it.
+		return fn(r)
+	}
+
+	txRepo, tx, release, err := r.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(txRepo); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
 // Ping checks if the database connection is alive.
 func (r *Repository) Ping(ctx context.Context) error {
 	sqlDB, err := r.db.DB()
@@ -81,24 +129,61 @@ func (r *Repository) SaveBroadcast(ctx context.Context, b domain.Broadcast) erro
 	return nil
 }
 
-// SaveMessages inserts a batch of messages inside a single transaction.
+// SaveBroadcastCounts stores the creation counts of b on its broadcast row.
+func (r *Repository) SaveBroadcastCounts(ctx context.Context, b domain.Broadcast) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Broadcast{}).
+		Where("id = ?", b.ID).
+		Updates(map[string]interface{}{
+			"queued":     b.Queued,
+			"rejected":   b.Rejected,
+			"suppressed": b.Suppressed,
+			"capped":     b.Capped,
+			"duplicates": b.Duplicates,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("save broadcast counts: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("broadcast not found: %s", b.ID)
+	}
+	return nil
+}
+
+// EnsureBroadcast inserts a broadcast unless one with the same ID exists.
+func (r *Repository) EnsureBroadcast(ctx context.Context, b domain.Broadcast) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, DoNothing: true}).
+		Create(&b).Error
+
+	if err != nil {
+		return fmt.Errorf("ensure broadcast: %w", err)
+	}
+	return nil
+}
+
+// SaveMessages inserts a batch of messages inside a single transaction,
+// using COPY for large batches. Called through WithinTx it joins the
+// caller's transaction instead.
 func (r *Repository) SaveMessages(ctx context.Context, msgs []domain.Message) error {
 	if len(msgs) == 0 {
 		return nil
 	}
 
-	if err := r.db.WithContext(ctx).CreateInBatches(msgs, 100).Error; err != nil {
-		return fmt.Errorf("create messages: %w", err)
+	if len(msgs) >= copyThreshold {
+		return r.CopyMessages(ctx, msgs)
 	}
-	return nil
+	return r.InsertMessages(ctx, msgs)
 }
 
-// GetPendingMessages returns up to limit messages with StatusPending.
+// GetPendingMessages returns up to limit messages with StatusPending that
+// are due, i.e. whose NotBefore has passed.
 func (r *Repository) GetPendingMessages(ctx context.Context, limit int) ([]domain.Message, error) {
 	var msgs []domain.Message
 	err := r.db.WithContext(ctx).
-		Where("status = ?", domain.StatusPending).
-		Order("created_at ASC").
+		Where("status = ? AND not_before <= ?", domain.StatusPending, time.Now().UTC()).
+		Order("not_before ASC").
 		Limit(limit).
 		Find(&msgs).Error
 
@@ -108,6 +193,50 @@ func (r *Repository) GetPendingMessages(ctx context.Context, limit int) ([]domai
 	return msgs, nil
 }
 
+// DeferMessages moves NotBefore of still pending messages forward to until.
+func (r *Repository) DeferMessages(ctx context.Context, ids []uuid.UUID, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.Message{}).
+		Where("id IN ? AND status = ?", ids, domain.StatusPending).
+		Updates(map[string]interface{}{
+			"not_before": until,
+			"updated_at": time.Now().UTC(),
+		}).Error
+
+	if err != nil {
+		return fmt.Errorf("defer messages: %w", err)
+	}
+	return nil
+}
+
+// DeliveryWindows returns the delivery windows of the given broadcasts,
+// omitting broadcasts that may be sent at any time.
+func (r *Repository) DeliveryWindows(ctx context.Context, broadcastIDs []uuid.UUID) (map[uuid.UUID]domain.DeliveryWindow, error) {
+	windows := make(map[uuid.UUID]domain.DeliveryWindow)
+	if len(broadcastIDs) == 0 {
+		return windows, nil
+	}
+
+	var broadcasts []domain.Broadcast
+	err := r.db.WithContext(ctx).
+		Select("id", "window_start", "window_end").
+		Where("id IN ? AND window_start <> window_end", broadcastIDs).
+		Find(&broadcasts).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("get delivery windows: %w", err)
+	}
+
+	for _, b := range broadcasts {
+		windows[b.ID] = b.Window
+	}
+	return windows, nil
+}
+
 // UpdateMessageStatus transitions a message to the given status.
 func (r *Repository) UpdateMessageStatus(ctx context.Context, id uuid.UUID, status domain.Status) error {
 	result := r.db.WithContext(ctx).
@@ -129,34 +258,102 @@ func (r *Repository) UpdateMessageStatus(ctx context.Context, id uuid.UUID, stat
 	return nil
 }
 
-// UpdateMessageStatusByProviderID transitions a message by the provider's external ID.
-func (r *Repository) UpdateMessageStatusByProviderID(ctx context.Context, providerID string, status domain.Status) error {
+// SuppressMessage marks a queued or pending message suppressed with the
+// reason it was not sent.
+func (r *Repository) SuppressMessage(ctx context.Context, id uuid.UUID, errorCode, errorDescription string) (bool, error) {
 	result := r.db.WithContext(ctx).
 		Model(&domain.Message{}).
-		Where("provider_id = ?", providerID).
+		Where("id = ? AND status IN ?", id, []domain.Status{domain.StatusQueued, domain.StatusPending}).
 		Updates(map[string]interface{}{
-			"status":     status,
-			"updated_at": time.Now().UTC(),
+			"status":            domain.StatusSuppressed,
+			"error_code":        errorCode,
+			"error_description": errorDescription,
+			"updated_at":        time.Now().UTC(),
 		})
 
 	if result.Error != nil {
-		return fmt.Errorf("update message status by provider id: %w", result.Error)
+		return false, fmt.Errorf("suppress message: %w", result.Error)
 	}
+	return result.RowsAffected > 0, nil
+}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("message not found for provider_id: %s", providerID)
+// CountMarketingMessages counts, per number, the messages of marketing
+// broadcasts created after since that are in one of the given statuses.
+// Numbers without such messages are absent from the result.
+func (r *Repository) CountMarketingMessages(ctx context.Context, phones []string, since time.Time, statuses []domain.Status) (map[string]int, error) {
+	counts := make(map[string]int)
+	for start := 0; start < len(phones); start += lookupChunkSize {
+		end := min(start+lookupChunkSize, len(phones))
+
+		var rows []struct {
+			ToNumber string
+			Count    int
+		}
+		err := r.db.WithContext(ctx).
+			Table("messages AS m").
+			Select("m.to_number, COUNT(*) AS count").
+			Joins("JOIN broadcasts AS b ON b.id = m.broadcast_id").
+			Where("m.to_number IN ? AND m.created_at > ? AND m.status IN ? AND b.category = ?",
+				phones[start:end], since, statuses, domain.CategoryMarketing).
+			Group("m.to_number").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("count marketing messages: %w", err)
+		}
+
+		for _, row := range rows {
+			counts[row.ToNumber] = row.Count
+		}
 	}
+	return counts, nil
+}
 
-	return nil
+// RecentContentHashes returns which of the given content hashes belong to
+// messages created after since that are in one of the given statuses.
+func (r *Repository) RecentContentHashes(ctx context.Context, hashes []string, since time.Time, statuses []domain.Status) (map[string]bool, error) {
+	found := make(map[string]bool)
+	for start := 0; start < len(hashes); start += lookupChunkSize {
+		end := min(start+lookupChunkSize, len(hashes))
+
+		var matched []string
+		err := r.db.WithContext(ctx).
+			Model(&domain.Message{}).
+			Distinct("content_hash").
+			Where("content_hash IN ? AND created_at > ? AND status IN ?", hashes[start:end], since, statuses).
+			Pluck("content_hash", &matched).Error
+		if err != nil {
+			return nil, fmt.Errorf("find recent content hashes: %w", err)
+		}
+
+		for _, hash := range matched {
+			found[hash] = true
+		}
+	}
+	return found, nil
+}
+
+// BroadcastCategory returns the category of a broadcast.
+func (r *Repository) BroadcastCategory(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	var b domain.Broadcast
+	err := r.db.WithContext(ctx).Select("category").Where("id = ?", id).First(&b).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", domain.ErrBroadcastNotFound
+		}
+		return "", fmt.Errorf("get broadcast category: %w", err)
+	}
+	return b.Category, nil
 }
 
-// SetProviderID stores the external SMS provider ID on a message after submission.
-func (r *Repository) SetProviderID(ctx context.Context, id uuid.UUID, providerID string) error {
+// SetProviderID stores the provider name and external SMS provider ID on a
+// message after submission.
+func (r *Repository) SetProviderID(ctx context.Context, id uuid.UUID, ref domain.ProviderRef) error {
 	result := r.db.WithContext(ctx).
 		Model(&domain.Message{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
-			"provider_id": providerID,
+			"provider":    ref.Provider,
+			"provider_id": ref.ProviderID,
 			"updated_at":  time.Now().UTC(),
 		})
 
@@ -171,6 +368,18 @@ func (r *Repository) SetProviderID(ctx context.Context, id uuid.UUID, providerID
 	return nil
 }
 
+// GetMessage retrieves a single message by ID.
+func (r *Repository) GetMessage(ctx context.Context, id uuid.UUID) (*domain.Message, error) {
+	var msg domain.Message
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&msg).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("get message: %w", err)
+	}
+	return &msg, nil
+}
+
 // GetBroadcast retrieves a broadcast by ID with all its messages.
 func (r *Repository) GetBroadcast(ctx context.Context, id uuid.UUID) (*domain.Broadcast, error) {
 	var broadcast domain.Broadcast
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"fmt"
+
+	"golang-sms-broadcast/internal/domain"
+)
+
+// numericPattern guards the numeric cast so a non-numeric attribute value
+// simply fails to match instead of aborting the query.
+const numericPattern = `^-?[0-9]+(\.[0-9]+)?$`
+
+// segmentSQL translates a segment into a WHERE fragment over the contacts
+// table. Field names and values are always bound as parameters.
+func segmentSQL(seg domain.Segment) (string, []interface{}, error) {
+	switch s := seg.(type) {
+	case domain.SegmentAnd:
+		return segmentBinary(s.Left, s.Right, "AND")
+	case domain.SegmentOr:
+		return segmentBinary(s.Left, s.Right, "OR")
+	case domain.SegmentNot:
+		inner, args, err := segmentSQL(s.Inner)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + inner + ")", args, nil
+	case domain.SegmentCondition:
+		return conditionSQL(s)
+	}
+	return "", nil, fmt.Errorf("unsupported segment node %T", seg)
+}
+
+func segmentBinary(left, right domain.Segment, op string) (string, []interface{}, error) {
+	l, largs, err := segmentSQL(left)
+	if err != nil {
+		return "", nil, err
+	}
+	r, rargs, err := segmentSQL(right)
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + l + ") " + op + " (" + r + ")", append(largs, rargs...), nil
+}
+
+// conditionSQL compares as text for = != IN and NOT IN, where a missing
+// attribute counts as the empty string, and numerically for < <= > >=.
+func conditionSQL(c domain.SegmentCondition) (string, []interface{}, error) {
+	text := "COALESCE(contacts.attributes->>?, '')"
+	var fieldArgs []interface{}
+	if c.Field == "phone" {
+		text = "contacts.phone"
+	} else {
+		fieldArgs = []interface{}{c.Field}
+	}
+
+	switch c.Op {
+	case "=", "!=":
+		return text + " " + c.Op + " ?", append(fieldArgs, c.Values[0].Text), nil
+
+	case "IN", "NOT IN":
+		values := make([]string, len(c.Values))
+		for i, v := range c.Values {
+			values[i] = v.Text
+		}
+		return text + " " + c.Op + " ?", append(fieldArgs, values), nil
+
+	case "<", "<=", ">", ">=":
+		// The pattern is bound too: a literal "?" would be taken for a placeholder.
+		numeric := fmt.Sprintf("(CASE WHEN %s ~ ? THEN (%s)::numeric END)", text, text)
+		args := append([]interface{}{}, fieldArgs...)
+		args = append(args, numericPattern)
+		args = append(args, fieldArgs...)
+		args = append(args, c.Values[0].Number)
+		return numeric + " " + c.Op + " ?", args, nil
+	}
+
+	return "", nil, fmt.Errorf("unsupported segment operator %q", c.Op)
+}
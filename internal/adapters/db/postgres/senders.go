@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// CreateSender registers a sender ID.
+func (r *Repository) CreateSender(ctx context.Context, s domain.SenderID) error {
+	if err := r.db.WithContext(ctx).Create(&s).Error; err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrSenderExists
+		}
+		return fmt.Errorf("create sender: %w", err)
+	}
+	return nil
+}
+
+// UpdateSender replaces a sender's type, country codes and description.
+func (r *Repository) UpdateSender(ctx context.Context, s domain.SenderID) error {
+	codes, err := json.Marshal(s.CountryCodes)
+	if err != nil {
+		return fmt.Errorf("marshal country codes: %w", err)
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&domain.SenderID{}).
+		Where("sender = ?", s.Sender).
+		Updates(map[string]interface{}{
+			"type":          s.Type,
+			"country_codes": string(codes),
+			"description":   s.Description,
+			"updated_at":    time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("update sender: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrSenderNotFound
+	}
+
+	return nil
+}
+
+// DeleteSender removes a sender ID. Broadcasts keep the sender they were sent from.
+func (r *Repository) DeleteSender(ctx context.Context, sender string) error {
+	result := r.db.WithContext(ctx).Where("sender = ?", sender).Delete(&domain.SenderID{})
+	if result.Error != nil {
+		return fmt.Errorf("delete sender: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrSenderNotFound
+	}
+	return nil
+}
+
+// GetSender retrieves a sender ID.
+func (r *Repository) GetSender(ctx context.Context, sender string) (domain.SenderID, error) {
+	var s domain.SenderID
+	if err := r.db.WithContext(ctx).Where("sender = ?", sender).First(&s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.SenderID{}, domain.ErrSenderNotFound
+		}
+		return domain.SenderID{}, fmt.Errorf("get sender: %w", err)
+	}
+	return s, nil
+}
+
+// ListSenders returns all sender IDs ordered by name.
+func (r *Repository) ListSenders(ctx context.Context) ([]domain.SenderID, error) {
+	var senders []domain.SenderID
+	if err := r.db.WithContext(ctx).Order("sender ASC").Find(&senders).Error; err != nil {
+		return nil, fmt.Errorf("list senders: %w", err)
+	}
+	return senders, nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"gorm.io/gorm/clause"
+)
+
+// lookupChunkSize keeps IN lists well below PostgreSQL's limit of
+// 65535 bind parameters when checking large recipient lists.
+const lookupChunkSize = 10000
+
+// AddSuppressions stores entries, ignoring ones already on the list.
+func (r *Repository) AddSuppressions(ctx context.Context, entries []domain.Suppression) (int64, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(entries, 1000)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("add suppressions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// RemoveSuppression deletes one entry.
+func (r *Repository) RemoveSuppression(ctx context.Context, phone, sender string) error {
+	result := r.db.WithContext(ctx).
+		Where("phone = ? AND sender = ?", phone, sender).
+		Delete(&domain.Suppression{})
+
+	if result.Error != nil {
+		return fmt.Errorf("remove suppression: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrSuppressionNotFound
+	}
+
+	return nil
+}
+
+// ListSuppressions returns a page of entries, newest first.
+func (r *Repository) ListSuppressions(ctx context.Context, filter ports.SuppressionFilter) ([]domain.Suppression, error) {
+	q := r.db.WithContext(ctx).Model(&domain.Suppression{})
+	if filter.Sender != nil {
+		q = q.Where("sender = ?", *filter.Sender)
+	}
+	if filter.Phone != "" {
+		q = q.Where("phone = ?", filter.Phone)
+	}
+
+	var entries []domain.Suppression
+	err := q.Order("created_at DESC, phone ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&entries).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("list suppressions: %w", err)
+	}
+	return entries, nil
+}
+
+// SuppressedPhones returns which of phones may not be messaged by sender.
+func (r *Repository) SuppressedPhones(ctx context.Context, sender string, phones []string) (map[string]bool, error) {
+	suppressed := make(map[string]bool)
+
+	for start := 0; start < len(phones); start += lookupChunkSize {
+		end := min(start+lookupChunkSize, len(phones))
+
+		var found []string
+		err := r.db.WithContext(ctx).
+			Model(&domain.Suppression{}).
+			Distinct("phone").
+			Where("phone IN ? AND sender IN ?", phones[start:end], []string{"", sender}).
+			Pluck("phone", &found).Error
+
+		if err != nil {
+			return nil, fmt.Errorf("find suppressed phones: %w", err)
+		}
+		for _, phone := range found {
+			suppressed[phone] = true
+		}
+	}
+
+	return suppressed, nil
+}
+
+// SuppressedSince reports whether phone was suppressed for sender after since.
+func (r *Repository) SuppressedSince(ctx context.Context, sender, phone string, since time.Time) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.Suppression{}).
+		Where("phone = ? AND sender IN ? AND created_at > ?", phone, []string{"", sender}, since.UTC()).
+		Count(&n).Error
+
+	if err != nil {
+		return false, fmt.Errorf("check suppression: %w", err)
+	}
+	return n > 0, nil
+}
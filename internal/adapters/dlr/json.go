@@ -0,0 +1,90 @@
+package dlr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+// JSONParser handles our generic JSON receipt format, also used by the mock provider.
+//
+// Body: { "provider_id": "...", "status": "delivered"|"failed"|..., "error_code": "...",
+//
+//	"error_description": "...", "delivered_at": "RFC3339" }
+type JSONParser struct{}
+
+type jsonReceipt struct {
+	ProviderID       string    `json:"provider_id"`
+	Status           string    `json:"status"`
+	ErrorCode        string    `json:"error_code"`
+	ErrorDescription string    `json:"error_description"`
+	DeliveredAt      time.Time `json:"delivered_at"`
+}
+
+// jsonStatuses maps the generic format's statuses onto the domain set.
+var jsonStatuses = map[string]domain.Status{
+	"sent":          domain.StatusSent,
+	"delivered":     domain.StatusDelivered,
+	"failed":        domain.StatusFailed,
+	"expired":       domain.StatusFailed,
+	"undeliverable": domain.StatusFailed,
+}
+
+// Parse implements ports.DLRParser.
+func (JSONParser) Parse(_ string, body []byte) (ports.DLRPayload, error) {
+	var r jsonReceipt
+	if err := json.Unmarshal(body, &r); err != nil {
+		return ports.DLRPayload{}, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	if r.ProviderID == "" || r.Status == "" {
+		return ports.DLRPayload{}, fmt.Errorf("%w: provider_id and status are required", ErrMalformed)
+	}
+
+	status, ok := jsonStatuses[r.Status]
+	if !ok {
+		return ports.DLRPayload{}, fmt.Errorf("%w: %q", domain.ErrUnknownStatus, r.Status)
+	}
+
+	return ports.DLRPayload{
+		ProviderID:       r.ProviderID,
+		Status:           withErrorCode(status, r.ErrorCode),
+		ErrorCode:        r.ErrorCode,
+		ErrorDescription: r.ErrorDescription,
+		DoneAt:           r.DeliveredAt,
+	}, nil
+}
+
+type jsonInbound struct {
+	ProviderID string    `json:"provider_id"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Body       string    `json:"body"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// ParseInbound implements ports.InboundParser.
+//
+// Body: { "provider_id": "...", "from": "+66...", "to": "...", "body": "STOP", "received_at": "RFC3339" }
+func (JSONParser) ParseInbound(_ string, _ url.Values, body []byte) (ports.InboundMessage, error) {
+	var m jsonInbound
+	if err := json.Unmarshal(body, &m); err != nil {
+		return ports.InboundMessage{}, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	if m.From == "" {
+		return ports.InboundMessage{}, fmt.Errorf("%w: from is required", ErrMalformed)
+	}
+
+	return ports.InboundMessage{
+		ProviderID: m.ProviderID,
+		From:       m.From,
+		To:         m.To,
+		Body:       m.Body,
+		ReceivedAt: m.ReceivedAt,
+	}, nil
+}
@@ -0,0 +1,52 @@
+// Package dlr contains ports.DLRParser and ports.InboundParser
+// implementations for the delivery receipt and inbound message formats used
+// by our SMS providers.
+package dlr
+
+import (
+	"errors"
+	"strings"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+// ErrMalformed is returned when a receipt cannot be decoded.
+var ErrMalformed = errors.New("malformed delivery receipt")
+
+// Parsers returns the parser registry keyed by the provider name used in the
+// /dlr/:provider route. The bare /dlr route uses the "mock" entry.
+func Parsers() map[string]ports.DLRParser {
+	return map[string]ports.DLRParser{
+		"mock":    JSONParser{},
+		"generic": JSONParser{},
+		"twilio":  TwilioParser{},
+		"smpp":    SMPPParser{},
+	}
+}
+
+// InboundParsers returns the inbound message parser registry keyed by the
+// provider name used in the /inbound/:provider route. The bare /inbound route
+// uses the "mock" entry.
+func InboundParsers() map[string]ports.InboundParser {
+	return map[string]ports.InboundParser{
+		"mock":    JSONParser{},
+		"generic": JSONParser{},
+		"twilio":  TwilioParser{},
+		"smpp":    SMPPParser{},
+	}
+}
+
+// withErrorCode marks a receipt as failed when the carrier reported an error
+// code but the status itself is not a confirmed delivery.
+func withErrorCode(status domain.Status, code string) domain.Status {
+	if status != domain.StatusDelivered && hasErrorCode(code) {
+		return domain.StatusFailed
+	}
+	return status
+}
+
+// hasErrorCode reports whether code is set and not an all-zero "no error" value.
+func hasErrorCode(code string) bool {
+	return strings.Trim(code, "0 ") != ""
+}
@@ -0,0 +1,190 @@
+package dlr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+type parseCase struct {
+	name    string
+	body    string
+	want    ports.DLRPayload
+	wantErr error
+}
+
+func runParseCases(t *testing.T, parser ports.DLRParser, contentType string, tests []parseCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse(contentType, []byte(tt.body))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("payload = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONParser(t *testing.T) {
+	deliveredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	runParseCases(t, JSONParser{}, "application/json", []parseCase{
+		{
+			name: "delivered",
+			body: `{"provider_id":"p-1","status":"delivered","delivered_at":"2026-01-02T03:04:05Z"}`,
+			want: ports.DLRPayload{ProviderID: "p-1", Status: domain.StatusDelivered, DoneAt: deliveredAt},
+		},
+		{
+			name: "sent",
+			body: `{"provider_id":"p-1","status":"sent"}`,
+			want: ports.DLRPayload{ProviderID: "p-1", Status: domain.StatusSent},
+		},
+		{
+			name: "failed with error",
+			body: `{"provider_id":"p-1","status":"failed","error_code":"30003","error_description":"Unreachable"}`,
+			want: ports.DLRPayload{ProviderID: "p-1", Status: domain.StatusFailed, ErrorCode: "30003", ErrorDescription: "Unreachable"},
+		},
+		{
+			name: "expired maps to failed",
+			body: `{"provider_id":"p-1","status":"expired"}`,
+			want: ports.DLRPayload{ProviderID: "p-1", Status: domain.StatusFailed},
+		},
+		{
+			name: "sent with error code fails",
+			body: `{"provider_id":"p-1","status":"sent","error_code":"12"}`,
+			want: ports.DLRPayload{ProviderID: "p-1", Status: domain.StatusFailed, ErrorCode: "12"},
+		},
+		{
+			name: "zero error code is no error",
+			body: `{"provider_id":"p-1","status":"sent","error_code":"000"}`,
+			want: ports.DLRPayload{ProviderID: "p-1", Status: domain.StatusSent, ErrorCode: "000"},
+		},
+		{
+			name:    "unknown status",
+			body:    `{"provider_id":"p-1","status":"bounced"}`,
+			wantErr: domain.ErrUnknownStatus,
+		},
+		{
+			name:    "internal status is not a receipt status",
+			body:    `{"provider_id":"p-1","status":"pending"}`,
+			wantErr: domain.ErrUnknownStatus,
+		},
+		{
+			name:    "missing provider id",
+			body:    `{"status":"delivered"}`,
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "invalid json",
+			body:    `{"provider_id":`,
+			wantErr: ErrMalformed,
+		},
+	})
+}
+
+func TestTwilioParser(t *testing.T) {
+	runParseCases(t, TwilioParser{}, "application/x-www-form-urlencoded", []parseCase{
+		{
+			name: "delivered",
+			body: "MessageSid=SM1&MessageStatus=delivered",
+			want: ports.DLRPayload{ProviderID: "SM1", Status: domain.StatusDelivered},
+		},
+		{
+			name: "legacy field names",
+			body: "SmsSid=SM1&SmsStatus=sent",
+			want: ports.DLRPayload{ProviderID: "SM1", Status: domain.StatusSent},
+		},
+		{
+			name: "status is case insensitive",
+			body: "MessageSid=SM1&MessageStatus=Queued",
+			want: ports.DLRPayload{ProviderID: "SM1", Status: domain.StatusSent},
+		},
+		{
+			name: "undelivered with known error code",
+			body: "MessageSid=SM1&MessageStatus=undelivered&ErrorCode=30003",
+			want: ports.DLRPayload{ProviderID: "SM1", Status: domain.StatusFailed, ErrorCode: "30003", ErrorDescription: "Unreachable destination handset"},
+		},
+		{
+			name: "error message wins over the code table",
+			body: "MessageSid=SM1&MessageStatus=failed&ErrorCode=30007&ErrorMessage=Spam",
+			want: ports.DLRPayload{ProviderID: "SM1", Status: domain.StatusFailed, ErrorCode: "30007", ErrorDescription: "Spam"},
+		},
+		{
+			name: "sent with error code fails",
+			body: "MessageSid=SM1&MessageStatus=sent&ErrorCode=30005",
+			want: ports.DLRPayload{ProviderID: "SM1", Status: domain.StatusFailed, ErrorCode: "30005", ErrorDescription: "Unknown destination handset"},
+		},
+		{
+			name:    "unknown status",
+			body:    "MessageSid=SM1&MessageStatus=read",
+			wantErr: domain.ErrUnknownStatus,
+		},
+		{
+			name:    "missing sid",
+			body:    "MessageStatus=delivered",
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "invalid form",
+			body:    "MessageSid=%zz",
+			wantErr: ErrMalformed,
+		},
+	})
+}
+
+func TestSMPPParser(t *testing.T) {
+	runParseCases(t, SMPPParser{}, "text/plain", []parseCase{
+		{
+			name: "delivered",
+			body: "id:abc123 sub:001 dlvrd:001 submit date:2601020300 done date:2601020304 stat:DELIVRD err:000 text:Hello",
+			want: ports.DLRPayload{ProviderID: "abc123", Status: domain.StatusDelivered, ErrorCode: "000", DoneAt: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)},
+		},
+		{
+			name: "done date with seconds",
+			body: "id:abc123 done date:260102030405 stat:DELIVRD err:0",
+			want: ports.DLRPayload{ProviderID: "abc123", Status: domain.StatusDelivered, ErrorCode: "0", DoneAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+		{
+			name: "undeliverable",
+			body: "id:abc123 stat:UNDELIV err:011",
+			want: ports.DLRPayload{ProviderID: "abc123", Status: domain.StatusFailed, ErrorCode: "011", ErrorDescription: "Message undeliverable"},
+		},
+		{
+			name: "lower case state",
+			body: "id:abc123 stat:enroute err:000",
+			want: ports.DLRPayload{ProviderID: "abc123", Status: domain.StatusSent, ErrorCode: "000"},
+		},
+		{
+			name: "unknown state with error code fails",
+			body: "id:abc123 stat:UNKNOWN err:042",
+			want: ports.DLRPayload{ProviderID: "abc123", Status: domain.StatusFailed, ErrorCode: "042", ErrorDescription: "Message in unknown state"},
+		},
+		{
+			name:    "unknown state without error code",
+			body:    "id:abc123 stat:UNKNOWN err:000",
+			wantErr: domain.ErrUnknownStatus,
+		},
+		{
+			name:    "subscriber message is not a receipt",
+			body:    "STOP",
+			wantErr: domain.ErrNotReceipt,
+		},
+		{
+			name:    "missing id",
+			body:    "sub:001 stat:DELIVRD err:000",
+			wantErr: ErrMalformed,
+		},
+	})
+}
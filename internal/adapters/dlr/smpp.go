@@ -0,0 +1,125 @@
+package dlr
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+// SMPPParser handles deliver_sm PDUs forwarded to us verbatim by
+// SMPP-to-HTTP gateways: delivery receipts, and inbound messages whose
+// addresses the gateway adds as query parameters.
+//
+// Receipt body: id:IIIIIIIIII sub:SSS dlvrd:DDD submit date:YYMMDDhhmm done date:YYMMDDhhmm stat:DDDDDDD err:E text:...
+type SMPPParser struct{}
+
+var (
+	smppID   = regexp.MustCompile(`(?i)\bid:(\S+)`)
+	smppStat = regexp.MustCompile(`(?i)\bstat:(\S+)`)
+	smppErr  = regexp.MustCompile(`(?i)\berr:(\S+)`)
+	smppDone = regexp.MustCompile(`(?i)\bdone date:(\d{10,12})`)
+)
+
+// smppStatuses maps SMPP message states onto the domain set.
+var smppStatuses = map[string]domain.Status{
+	"ENROUTE": domain.StatusSent,
+	"ACCEPTD": domain.StatusSent,
+	"DELIVRD": domain.StatusDelivered,
+	"EXPIRED": domain.StatusFailed,
+	"DELETED": domain.StatusFailed,
+	"UNDELIV": domain.StatusFailed,
+	"REJECTD": domain.StatusFailed,
+}
+
+// Parse implements ports.DLRParser.
+func (SMPPParser) Parse(_ string, body []byte) (ports.DLRPayload, error) {
+	text := string(body)
+
+	id := submatch(smppID, text)
+	stat := strings.ToUpper(submatch(smppStat, text))
+	if stat == "" {
+		// A deliver_sm without a receipt is a message from a subscriber.
+		return ports.DLRPayload{}, domain.ErrNotReceipt
+	}
+	if id == "" {
+		return ports.DLRPayload{}, fmt.Errorf("%w: id and stat are required", ErrMalformed)
+	}
+	code := submatch(smppErr, text)
+
+	status, ok := smppStatuses[stat]
+	if !ok {
+		// UNKNOWN (or a vendor-specific state) is only actionable with an error code.
+		if !hasErrorCode(code) {
+			return ports.DLRPayload{}, fmt.Errorf("%w: %q", domain.ErrUnknownStatus, stat)
+		}
+		status = domain.StatusFailed
+	}
+
+	var desc string
+	if status == domain.StatusFailed {
+		desc = smppStateNames[stat]
+	}
+
+	return ports.DLRPayload{
+		ProviderID:       id,
+		Status:           withErrorCode(status, code),
+		ErrorCode:        code,
+		ErrorDescription: desc,
+		DoneAt:           parseSMPPDate(submatch(smppDone, text)),
+	}, nil
+}
+
+// ParseInbound implements ports.InboundParser for a non-receipt deliver_sm.
+// The short message is the body; the gateway passes the PDU fields as query
+// parameters, e.g. ?source_addr=66812345678&source_addr_ton=1&destination_addr=4567
+func (SMPPParser) ParseInbound(_ string, query url.Values, body []byte) (ports.InboundMessage, error) {
+	from := query.Get("source_addr")
+	if from == "" {
+		return ports.InboundMessage{}, fmt.Errorf("%w: source_addr is required", ErrMalformed)
+	}
+	// TON 1 is an international number, which SMPP carries without the "+".
+	if query.Get("source_addr_ton") == "1" && !strings.HasPrefix(from, "+") {
+		from = "+" + from
+	}
+
+	return ports.InboundMessage{
+		ProviderID: query.Get("message_id"),
+		From:       from,
+		To:         query.Get("destination_addr"),
+		Body:       string(body),
+	}, nil
+}
+
+// smppStateNames describes the final SMPP message states.
+var smppStateNames = map[string]string{
+	"EXPIRED": "Validity period expired",
+	"DELETED": "Message deleted",
+	"UNDELIV": "Message undeliverable",
+	"REJECTD": "Message rejected",
+	"UNKNOWN": "Message in unknown state",
+}
+
+// parseSMPPDate parses YYMMDDhhmm[ss] as UTC; returns zero time if absent or invalid.
+func parseSMPPDate(s string) time.Time {
+	layout := "0601021504"
+	if len(s) == 12 {
+		layout = "060102150405"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+func submatch(re *regexp.Regexp, s string) string {
+	if m := re.FindStringSubmatch(s); len(m) == 2 {
+		return m[1]
+	}
+	return ""
+}
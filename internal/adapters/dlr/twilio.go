@@ -0,0 +1,104 @@
+package dlr
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+// TwilioParser handles Twilio-style form-encoded status callbacks.
+//
+// Body: MessageSid=SM...&MessageStatus=delivered&ErrorCode=30003
+type TwilioParser struct{}
+
+// twilioStatuses maps Twilio's MessageStatus values onto the domain set.
+var twilioStatuses = map[string]domain.Status{
+	"accepted":    domain.StatusSent,
+	"queued":      domain.StatusSent,
+	"sending":     domain.StatusSent,
+	"sent":        domain.StatusSent,
+	"delivered":   domain.StatusDelivered,
+	"undelivered": domain.StatusFailed,
+	"failed":      domain.StatusFailed,
+	"canceled":    domain.StatusFailed,
+}
+
+// twilioErrors describes the most common Twilio delivery error codes.
+var twilioErrors = map[string]string{
+	"30001": "Queue overflow",
+	"30002": "Account suspended",
+	"30003": "Unreachable destination handset",
+	"30004": "Message blocked",
+	"30005": "Unknown destination handset",
+	"30006": "Landline or unreachable carrier",
+	"30007": "Message filtered by carrier",
+	"30008": "Unknown error",
+	"30009": "Missing segment",
+	"30010": "Message price exceeds max price",
+}
+
+// Parse implements ports.DLRParser.
+func (TwilioParser) Parse(_ string, body []byte) (ports.DLRPayload, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ports.DLRPayload{}, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	sid := form.Get("MessageSid")
+	if sid == "" {
+		sid = form.Get("SmsSid")
+	}
+	raw := form.Get("MessageStatus")
+	if raw == "" {
+		raw = form.Get("SmsStatus")
+	}
+	if sid == "" || raw == "" {
+		return ports.DLRPayload{}, fmt.Errorf("%w: MessageSid and MessageStatus are required", ErrMalformed)
+	}
+
+	status, ok := twilioStatuses[strings.ToLower(raw)]
+	if !ok {
+		return ports.DLRPayload{}, fmt.Errorf("%w: %q", domain.ErrUnknownStatus, raw)
+	}
+
+	code := form.Get("ErrorCode")
+	desc := form.Get("ErrorMessage")
+	if desc == "" {
+		desc = twilioErrors[code]
+	}
+
+	return ports.DLRPayload{
+		ProviderID:       sid,
+		Status:           withErrorCode(status, code),
+		ErrorCode:        code,
+		ErrorDescription: desc,
+	}, nil
+}
+
+// ParseInbound implements ports.InboundParser for Twilio's incoming message webhook.
+//
+// Body: MessageSid=SM...&From=%2B66...&To=%2B1...&Body=STOP
+func (TwilioParser) ParseInbound(_ string, _ url.Values, body []byte) (ports.InboundMessage, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ports.InboundMessage{}, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	sid := form.Get("MessageSid")
+	if sid == "" {
+		sid = form.Get("SmsSid")
+	}
+	if form.Get("From") == "" {
+		return ports.InboundMessage{}, fmt.Errorf("%w: From is required", ErrMalformed)
+	}
+
+	return ports.InboundMessage{
+		ProviderID: sid,
+		From:       form.Get("From"),
+		To:         form.Get("To"),
+		Body:       form.Get("Body"),
+	}, nil
+}
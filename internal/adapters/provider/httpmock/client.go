@@ -15,13 +15,16 @@ import (
 // Client implements ports.SMSProvider by forwarding requests to a mock HTTP server.
 type Client struct {
 	baseURL    string
+	name       string
 	httpClient *http.Client
 }
 
-// New creates a Client targeting the given base URL.
-func New(baseURL string) *Client {
+// New creates a Client targeting the given base URL. name is the provider
+// name its delivery receipts arrive under (the /dlr/:provider route segment).
+func New(baseURL, name string) *Client {
 	return &Client{
 		baseURL: baseURL,
+		name:    name,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -31,6 +34,7 @@ func New(baseURL string) *Client {
 type sendRequest struct {
 	MessageID string `json:"message_id"`
 	To        string `json:"to"`
+	From      string `json:"from,omitempty"` // Sender ID; omitted for the provider's default
 	Body      string `json:"body"`
 	DLRHook   string `json:"dlr_webhook_url"`
 }
@@ -44,6 +48,7 @@ func (c *Client) Send(ctx context.Context, msg domain.Message) (ports.SendResult
 	payload := sendRequest{
 		MessageID: msg.ID.String(),
 		To:        msg.To,
+		From:      msg.Sender,
 		Body:      msg.Body,
 	}
 
@@ -73,5 +78,5 @@ func (c *Client) Send(ctx context.Context, msg domain.Message) (ports.SendResult
 		return ports.SendResult{}, fmt.Errorf("decode response: %w", err)
 	}
 
-	return ports.SendResult{ProviderID: sr.ProviderID}, nil
+	return ports.SendResult{Provider: c.name, ProviderID: sr.ProviderID}, nil
 }
@@ -47,49 +47,69 @@ func NewConsumer(amqpURL string, log *slog.Logger) (*Consumer, error) {
 	return &Consumer{conn: conn, channel: ch, log: log}, nil
 }
 
-// Consume registers a consumer on the queue and calls handler for each delivery.
+// Consume registers consumers on the main and priority queues and calls
+// handler for each delivery, taking a waiting priority message first.
 // It acknowledges the message only if the handler returns nil.
 // It blocks until ctx is cancelled.
 func (c *Consumer) Consume(ctx context.Context, handler func(ctx context.Context, msg domain.Message) error) error {
-	deliveries, err := c.channel.Consume(
-		queueName,
-		"",    // auto-generated consumer tag
-		false, // manual ack
-		false, // exclusive
-		false, // no-local
-		false, // no-wait
-		nil,
-	)
+	deliveries, err := c.consume(queueName)
 	if err != nil {
-		return fmt.Errorf("consume: %w", err)
+		return err
+	}
+	priority, err := c.consume(priorityQueueName)
+	if err != nil {
+		return err
 	}
 
 	for {
+		var d amqp.Delivery
+		var ok bool
 		select {
-		case <-ctx.Done():
-			return ctx.Err()
-
-		case d, ok := <-deliveries:
-			if !ok {
-				return fmt.Errorf("deliveries channel closed")
-			}
-
-			var msg domain.Message
-			if err := json.Unmarshal(d.Body, &msg); err != nil {
-				c.log.Error("unmarshal message", "err", err)
-				d.Nack(false, false) // dead-letter; don't requeue malformed payloads
-				continue
+		case d, ok = <-priority:
+		default:
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case d, ok = <-priority:
+			case d, ok = <-deliveries:
 			}
+		}
+		if !ok {
+			return fmt.Errorf("deliveries channel closed")
+		}
 
-			if err := handler(ctx, msg); err != nil {
-				c.log.Error("handler error", "msg_id", msg.ID, "err", err)
-				d.Nack(false, true) // requeue for retry
-				continue
-			}
+		var msg domain.Message
+		if err := json.Unmarshal(d.Body, &msg); err != nil {
+			c.log.Error("unmarshal message", "err", err)
+			d.Nack(false, false) // dead-letter; don't requeue malformed payloads
+			continue
+		}
 
-			d.Ack(false)
+		if err := handler(ctx, msg); err != nil {
+			c.log.Error("handler error", "msg_id", msg.ID, "err", err)
+			d.Nack(false, true) // requeue for retry
+			continue
 		}
+
+		d.Ack(false)
+	}
+}
+
+// consume starts delivery from one queue with manual acks.
+func (c *Consumer) consume(queue string) (<-chan amqp.Delivery, error) {
+	deliveries, err := c.channel.Consume(
+		queue,
+		"",    // auto-generated consumer tag
+		false, // manual ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("consume %s: %w", queue, err)
 	}
+	return deliveries, nil
 }
 
 // Close cleanly shuts down the channel and connection.
@@ -0,0 +1,286 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/ports"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const dlrQueueName = "sms.dlr"
+const dlrRoutingKey = "sms.dlr"
+
+// Receipts the processor rejects as malformed, or keeps failing to apply,
+// are dead-lettered to sms.dlr.dead through the sms.dlx exchange, where they
+// can be inspected.
+const dlxExchangeName = "sms.dlx"
+const dlrDeadQueueName = "sms.dlr.dead"
+
+// DLRPublisher implements ports.DLRPublisher using RabbitMQ.
+type DLRPublisher struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+// NewDLRPublisher dials RabbitMQ, declares the DLR queue and puts the channel
+// into confirm mode.
+func NewDLRPublisher(amqpURL string) (*DLRPublisher, error) {
+	conn, ch, err := dialDLR(amqpURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("enable publisher confirms: %w", err)
+	}
+
+	return &DLRPublisher{conn: conn, channel: ch}, nil
+}
+
+// PublishDLR serialises a receipt, sends it to the sms.dlr queue and waits
+// for the broker to confirm it, so the webhook only answers 202 once the
+// receipt is safely stored.
+func (p *DLRPublisher) PublishDLR(ctx context.Context, dlr ports.DLRPayload) error {
+	body, err := json.Marshal(dlr)
+	if err != nil {
+		return fmt.Errorf("marshal dlr: %w", err)
+	}
+
+	confirm, err := p.channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		exchangeName,
+		dlrRoutingKey,
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    dlr.ProviderID,
+			Body:         body,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("publish dlr: %w", err)
+	}
+
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("wait for dlr confirm: %w", err)
+	}
+	if !acked {
+		return errors.New("dlr publish was not acknowledged by the broker")
+	}
+	return nil
+}
+
+// Close cleanly shuts down the channel and connection.
+func (p *DLRPublisher) Close() {
+	p.channel.Close()
+	p.conn.Close()
+}
+
+// DLRConsumer implements ports.DLRConsumer using RabbitMQ.
+type DLRConsumer struct {
+	conn          *amqp.Connection
+	channel       *amqp.Channel
+	batchSize     int
+	flushInterval time.Duration
+	log           *slog.Logger
+}
+
+// NewDLRConsumer dials RabbitMQ, declares the DLR queue, and returns a consumer
+// that hands out batches of up to batchSize receipts, flushing partial batches
+// every flushInterval.
+func NewDLRConsumer(amqpURL string, batchSize int, flushInterval time.Duration, log *slog.Logger) (*DLRConsumer, error) {
+	conn, ch, err := dialDLR(amqpURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefetch a full batch so the broker keeps us busy between flushes.
+	if err := ch.Qos(batchSize, 0, false); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("set qos: %w", err)
+	}
+
+	return &DLRConsumer{
+		conn:          conn,
+		channel:       ch,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		log:           log,
+	}, nil
+}
+
+// ConsumeBatches collects receipts into batches and passes them to handler.
+// Deliveries are acknowledged only after the handler succeeds.
+// It blocks until ctx is cancelled.
+func (c *DLRConsumer) ConsumeBatches(ctx context.Context, handler ports.DLRBatchHandler) error {
+	deliveries, err := c.channel.Consume(
+		dlrQueueName,
+		"",    // auto-generated consumer tag
+		false, // manual ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("consume: %w", err)
+	}
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	pending := make([]amqp.Delivery, 0, c.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			for _, d := range pending {
+				d.Nack(false, true)
+			}
+			return ctx.Err()
+
+		case d, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("deliveries channel closed")
+			}
+			pending = append(pending, d)
+			if len(pending) >= c.batchSize {
+				c.flush(ctx, pending, handler)
+				pending = pending[:0]
+			}
+
+		case <-ticker.C:
+			if len(pending) > 0 {
+				c.flush(ctx, pending, handler)
+				pending = pending[:0]
+			}
+		}
+	}
+}
+
+// flush decodes a batch of deliveries, runs the handler and settles them.
+func (c *DLRConsumer) flush(ctx context.Context, deliveries []amqp.Delivery, handler ports.DLRBatchHandler) {
+	batch := make([]ports.DLRPayload, 0, len(deliveries))
+	valid := make([]amqp.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		var dlr ports.DLRPayload
+		if err := json.Unmarshal(d.Body, &dlr); err != nil {
+			c.log.Error("unmarshal dlr", "err", err)
+			d.Nack(false, false) // dead-letter to sms.dlr.dead; don't requeue malformed payloads
+			continue
+		}
+		batch = append(batch, dlr)
+		valid = append(valid, d)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := handler(ctx, batch); err != nil {
+		c.log.Error("dlr batch handler error", "size", len(batch), "err", err)
+		c.settleOneByOne(ctx, batch, valid, handler)
+		return
+	}
+
+	for _, d := range valid {
+		d.Ack(false)
+	}
+}
+
+// settleOneByOne applies the receipts of a failed batch one at a time, so
+// one bad receipt cannot hold back the others. When every receipt fails the
+// cause is most likely the database, and all of them are requeued. Otherwise
+// a receipt that fails again after a redelivery is dead-lettered to
+// sms.dlr.dead instead of looping forever.
+func (c *DLRConsumer) settleOneByOne(ctx context.Context, batch []ports.DLRPayload, deliveries []amqp.Delivery, handler ports.DLRBatchHandler) {
+	failed := make([]int, 0, len(batch))
+	for i := range batch {
+		if ctx.Err() != nil {
+			failed = append(failed, i)
+			continue
+		}
+		if err := handler(ctx, batch[i:i+1]); err != nil {
+			c.log.Error("dlr handler error", "provider_id", batch[i].ProviderID, "err", err)
+			failed = append(failed, i)
+			continue
+		}
+		deliveries[i].Ack(false)
+	}
+
+	allFailed := len(failed) == len(batch)
+	for _, i := range failed {
+		d := deliveries[i]
+		if allFailed || ctx.Err() != nil || !d.Redelivered {
+			d.Nack(false, true) // requeue for retry
+			continue
+		}
+		c.log.Error("dlr dead-lettered after repeated failures", "provider_id", batch[i].ProviderID)
+		d.Nack(false, false)
+	}
+}
+
+// Close cleanly shuts down the channel and connection.
+func (c *DLRConsumer) Close() {
+	c.channel.Close()
+	c.conn.Close()
+}
+
+func dialDLR(amqpURL string) (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := amqp.Dial(amqpURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dial rabbitmq: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("open channel: %w", err)
+	}
+
+	if err := declareDLR(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, ch, nil
+}
+
+// declareDLR idempotently sets up the exchange, DLR queue, its dead-letter
+// queue, and the bindings.
+func declareDLR(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(exchangeName, "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare exchange: %w", err)
+	}
+
+	if err := ch.ExchangeDeclare(dlxExchangeName, "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare dead-letter exchange: %w", err)
+	}
+	if _, err := ch.QueueDeclare(dlrDeadQueueName, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare dlr dead-letter queue: %w", err)
+	}
+	if err := ch.QueueBind(dlrDeadQueueName, dlrRoutingKey, dlxExchangeName, false, nil); err != nil {
+		return fmt.Errorf("bind dlr dead-letter queue: %w", err)
+	}
+
+	args := amqp.Table{"x-dead-letter-exchange": dlxExchangeName}
+	if _, err := ch.QueueDeclare(dlrQueueName, true, false, false, false, args); err != nil {
+		return fmt.Errorf("declare dlr queue: %w", err)
+	}
+	if err := ch.QueueBind(dlrQueueName, dlrRoutingKey, exchangeName, false, nil); err != nil {
+		return fmt.Errorf("bind dlr queue: %w", err)
+	}
+
+	return nil
+}
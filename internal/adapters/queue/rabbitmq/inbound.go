@@ -0,0 +1,170 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"golang-sms-broadcast/internal/ports"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const inboundQueueName = "sms.inbound"
+const inboundRoutingKey = "sms.inbound"
+
+// InboundPublisher implements ports.InboundPublisher using RabbitMQ.
+type InboundPublisher struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+// NewInboundPublisher dials RabbitMQ and declares the inbound queue.
+func NewInboundPublisher(amqpURL string) (*InboundPublisher, error) {
+	conn, ch, err := dialInbound(amqpURL)
+	if err != nil {
+		return nil, err
+	}
+	return &InboundPublisher{conn: conn, channel: ch}, nil
+}
+
+// PublishInbound serialises an inbound message and sends it to the sms.inbound queue.
+func (p *InboundPublisher) PublishInbound(ctx context.Context, msg ports.InboundMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal inbound message: %w", err)
+	}
+
+	return p.channel.PublishWithContext(
+		ctx,
+		exchangeName,
+		inboundRoutingKey,
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    msg.ProviderID,
+			Body:         body,
+		},
+	)
+}
+
+// Close cleanly shuts down the channel and connection.
+func (p *InboundPublisher) Close() {
+	p.channel.Close()
+	p.conn.Close()
+}
+
+// InboundConsumer implements ports.InboundConsumer using RabbitMQ.
+type InboundConsumer struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	log     *slog.Logger
+}
+
+// NewInboundConsumer dials RabbitMQ, declares the inbound queue, and returns
+// a consumer that handles one message at a time.
+func NewInboundConsumer(amqpURL string, log *slog.Logger) (*InboundConsumer, error) {
+	conn, ch, err := dialInbound(amqpURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replies from one subscriber are handled in the order they arrived.
+	if err := ch.Qos(1, 0, false); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("set qos: %w", err)
+	}
+
+	return &InboundConsumer{conn: conn, channel: ch, log: log}, nil
+}
+
+// ConsumeInbound calls handler for each inbound message and acknowledges it
+// only if the handler succeeds. It blocks until ctx is cancelled.
+func (c *InboundConsumer) ConsumeInbound(ctx context.Context, handler func(ctx context.Context, msg ports.InboundMessage) error) error {
+	deliveries, err := c.channel.Consume(
+		inboundQueueName,
+		"",    // auto-generated consumer tag
+		false, // manual ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("consume: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case d, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("deliveries channel closed")
+			}
+
+			var msg ports.InboundMessage
+			if err := json.Unmarshal(d.Body, &msg); err != nil {
+				c.log.Error("unmarshal inbound message", "err", err)
+				d.Nack(false, false) // dead-letter; don't requeue malformed payloads
+				continue
+			}
+
+			if err := handler(ctx, msg); err != nil {
+				c.log.Error("inbound handler error", "provider_id", msg.ProviderID, "err", err)
+				d.Nack(false, true) // requeue for retry
+				continue
+			}
+
+			d.Ack(false)
+		}
+	}
+}
+
+// Close cleanly shuts down the channel and connection.
+func (c *InboundConsumer) Close() {
+	c.channel.Close()
+	c.conn.Close()
+}
+
+func dialInbound(amqpURL string) (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := amqp.Dial(amqpURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dial rabbitmq: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("open channel: %w", err)
+	}
+
+	if err := declareInbound(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, ch, nil
+}
+
+// declareInbound idempotently sets up the exchange, inbound queue, and binding.
+func declareInbound(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(exchangeName, "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare exchange: %w", err)
+	}
+
+	if _, err := ch.QueueDeclare(inboundQueueName, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare inbound queue: %w", err)
+	}
+	if err := ch.QueueBind(inboundQueueName, inboundRoutingKey, exchangeName, false, nil); err != nil {
+		return fmt.Errorf("bind inbound queue: %w", err)
+	}
+
+	return nil
+}
@@ -14,6 +14,11 @@ const exchangeName = "sms"
 const queueName = "sms.send"
 const routingKey = "sms.send"
 
+// The priority lane carries high-priority messages such as one-time
+// passwords past any broadcast backlog on the main queue.
+const priorityQueueName = "sms.send.priority"
+const priorityRoutingKey = "sms.send.priority"
+
 // Publisher implements ports.MessagePublisher using RabbitMQ.
 type Publisher struct {
 	conn    *amqp.Connection
@@ -42,17 +47,23 @@ func NewPublisher(amqpURL string) (*Publisher, error) {
 	return &Publisher{conn: conn, channel: ch}, nil
 }
 
-// Publish serialises a domain.Message and sends it to the queue.
+// Publish serialises a domain.Message and sends it to the queue of its
+// priority lane.
 func (p *Publisher) Publish(ctx context.Context, msg domain.Message) error {
 	body, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("marshal message: %w", err)
 	}
 
+	key := routingKey
+	if msg.Priority == domain.PriorityHigh {
+		key = priorityRoutingKey
+	}
+
 	return p.channel.PublishWithContext(
 		ctx,
 		exchangeName,
-		routingKey,
+		key,
 		false, // mandatory
 		false, // immediate
 		amqp.Publishing{
@@ -70,18 +81,20 @@ func (p *Publisher) Close() {
 	p.conn.Close()
 }
 
-// declare idempotently sets up the exchange, queue, and binding.
+// declare idempotently sets up the exchange, queues, and bindings.
 func declare(ch *amqp.Channel) error {
 	if err := ch.ExchangeDeclare(exchangeName, "direct", true, false, false, false, nil); err != nil {
 		return fmt.Errorf("declare exchange: %w", err)
 	}
 
-	if _, err := ch.QueueDeclare(queueName, true, false, false, false, nil); err != nil {
-		return fmt.Errorf("declare queue: %w", err)
-	}
+	for queue, key := range map[string]string{queueName: routingKey, priorityQueueName: priorityRoutingKey} {
+		if _, err := ch.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("declare queue %s: %w", queue, err)
+		}
 
-	if err := ch.QueueBind(queueName, routingKey, exchangeName, false, nil); err != nil {
-		return fmt.Errorf("bind queue: %w", err)
+		if err := ch.QueueBind(queue, key, exchangeName, false, nil); err != nil {
+			return fmt.Errorf("bind queue %s: %w", queue, err)
+		}
 	}
 
 	return nil
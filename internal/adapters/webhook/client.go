@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/middleware"
+
+	"github.com/google/uuid"
+)
+
+// Client implements ports.CallbackSender by POSTing signed JSON to client applications.
+type Client struct {
+	secret     string
+	httpClient *http.Client
+}
+
+// New creates a Client that signs payloads with secret. It only connects to
+// public addresses, checked after DNS resolution so a callback host cannot
+// resolve its way into our network, and it does not follow redirects.
+func New(secret string, timeout time.Duration) *Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !domain.PublicIP(ip) {
+				return fmt.Errorf("%w: %s", domain.ErrPrivateAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &Client{
+		secret: secret,
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConnsPerHost: 10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts the event payload with the same HMAC scheme we require on inbound DLRs.
+// The event ID header lets receivers drop duplicates caused by retries.
+func (c *Client) Send(ctx context.Context, event domain.CallbackEvent) (int, error) {
+	body := []byte(event.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, event.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("new request: %w", err)
+	}
+
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID.String())
+	req.Header.Set("X-Event-Type", event.Event)
+	req.Header.Set(middleware.SignatureTimestampHeader, strconv.FormatInt(ts, 10))
+	nonce := uuid.NewString()
+	req.Header.Set(middleware.SignatureNonceHeader, nonce)
+	req.Header.Set(middleware.SignatureHeader, middleware.Sign(c.secret, ts, nonce, body))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("callback returned %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// CallbackPolicy controls how the dispatcher retries failed callbacks.
+type CallbackPolicy struct {
+	MaxAttempts int           // Attempts before an event is marked failed
+	BaseDelay   time.Duration // Delay after the first failure; doubles each attempt
+	MaxDelay    time.Duration // Upper bound for the backoff delay
+	Lease       time.Duration // How long a claimed event is hidden from other dispatchers
+	Concurrency int           // Callbacks in flight at once
+}
+
+// CallbackService delivers queued status events to client applications.
+type CallbackService struct {
+	repo   ports.CallbackRepository
+	sender ports.CallbackSender
+	policy CallbackPolicy
+	log    *slog.Logger
+}
+
+// NewCallbackService wires the service with its dependencies.
+func NewCallbackService(
+	repo ports.CallbackRepository,
+	sender ports.CallbackSender,
+	policy CallbackPolicy,
+	log *slog.Logger,
+) *CallbackService {
+	if policy.Concurrency < 1 {
+		policy.Concurrency = 1
+	}
+	return &CallbackService{
+		repo:   repo,
+		sender: sender,
+		policy: policy,
+		log:    log,
+	}
+}
+
+// DispatchDue claims up to batchSize due events, attempts each once and
+// returns how many were claimed. This is called by the callback-dispatcher
+// binary on a poll interval.
+func (s *CallbackService) DispatchDue(ctx context.Context, batchSize int) (int, error) {
+	events, err := s.repo.ClaimDueCallbacks(ctx, batchSize, s.policy.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim due callbacks: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.policy.Concurrency)
+	for _, event := range events {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(event domain.CallbackEvent) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.deliver(ctx, event)
+		}(event)
+	}
+	wg.Wait()
+
+	return len(events), nil
+}
+
+// deliver sends one event, logs the attempt and schedules a retry on failure.
+func (s *CallbackService) deliver(ctx context.Context, event domain.CallbackEvent) {
+	started := time.Now()
+	code, sendErr := s.sender.Send(ctx, event)
+
+	attempt := domain.CallbackAttempt{
+		EventID:     event.ID,
+		Attempt:     event.Attempts + 1,
+		StatusCode:  code,
+		DurationMS:  time.Since(started).Milliseconds(),
+		AttemptedAt: started.UTC(),
+	}
+
+	event.Attempts++
+	event.LastError = ""
+	switch {
+	case sendErr == nil:
+		event.Status = domain.CallbackDelivered
+	case event.Attempts >= s.policy.MaxAttempts:
+		event.Status = domain.CallbackFailed
+		event.LastError = sendErr.Error()
+		attempt.Error = sendErr.Error()
+	default:
+		event.Status = domain.CallbackPending
+		event.NextAttemptAt = time.Now().Add(s.backoff(event.Attempts))
+		event.LastError = sendErr.Error()
+		attempt.Error = sendErr.Error()
+	}
+
+	if err := s.repo.RecordCallbackAttempt(ctx, event, attempt); err != nil {
+		// The lease expires and the event is retried; the client may see a duplicate.
+		s.log.Error("record callback attempt failed", "event_id", event.ID, "err", err)
+		return
+	}
+
+	if sendErr != nil {
+		s.log.Warn("callback failed", "event_id", event.ID, "event", event.Event, "attempt", event.Attempts, "status", event.Status, "err", sendErr)
+		return
+	}
+
+	s.log.Info("callback delivered", "event_id", event.ID, "event", event.Event, "attempt", event.Attempts)
+}
+
+// backoff returns the exponential delay before the given attempt is retried,
+// with up to 20% jitter so failing endpoints are not hit in lockstep.
+func (s *CallbackService) backoff(attempts int) time.Duration {
+	delay := s.policy.BaseDelay
+	for i := 1; i < attempts && delay < s.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.policy.MaxDelay {
+		delay = s.policy.MaxDelay
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+// GetCallback returns an event and its delivery log.
+func (s *CallbackService) GetCallback(ctx context.Context, id uuid.UUID) (*domain.CallbackEvent, []domain.CallbackAttempt, error) {
+	return s.repo.GetCallback(ctx, id)
+}
+
+// ListCallbacks returns the most recent events of a broadcast.
+func (s *CallbackService) ListCallbacks(ctx context.Context, broadcastID uuid.UUID, limit int) ([]domain.CallbackEvent, error) {
+	return s.repo.ListCallbacks(ctx, broadcastID, limit)
+}
+
+// ReplayCallback schedules an event for immediate redelivery, whatever its state.
+func (s *CallbackService) ReplayCallback(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.ResetCallback(ctx, id); err != nil {
+		return fmt.Errorf("reset callback: %w", err)
+	}
+	s.log.Info("callback replay requested", "event_id", id)
+	return nil
+}
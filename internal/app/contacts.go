@@ -0,0 +1,297 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// importChunkSize is how many contacts are upserted per statement.
+const importChunkSize = 1000
+
+// ContactService manages reusable contacts and contact lists.
+type ContactService struct {
+	repo ports.ContactRepository
+	log  *slog.Logger
+}
+
+// NewContactService wires the service with its dependencies.
+func NewContactService(repo ports.ContactRepository, log *slog.Logger) *ContactService {
+	return &ContactService{repo: repo, log: log}
+}
+
+// CreateContact validates and stores a new contact.
+func (s *ContactService) CreateContact(ctx context.Context, phone string, attributes map[string]string) (domain.Contact, error) {
+	phone, err := domain.NormalizePhone(phone)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+
+	now := time.Now().UTC()
+	c := domain.Contact{ID: uuid.New(), Phone: phone, Attributes: attributes, CreatedAt: now, UpdatedAt: now}
+	if c.Attributes == nil {
+		c.Attributes = map[string]string{}
+	}
+
+	if err := s.repo.SaveContact(ctx, c); err != nil {
+		return domain.Contact{}, fmt.Errorf("save contact: %w", err)
+	}
+	return c, nil
+}
+
+// UpdateContact replaces a contact's phone and attributes.
+func (s *ContactService) UpdateContact(ctx context.Context, id uuid.UUID, phone string, attributes map[string]string) (domain.Contact, error) {
+	c, err := s.repo.GetContact(ctx, id)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+
+	if c.Phone, err = domain.NormalizePhone(phone); err != nil {
+		return domain.Contact{}, err
+	}
+	c.Attributes = attributes
+	if c.Attributes == nil {
+		c.Attributes = map[string]string{}
+	}
+
+	if err := s.repo.UpdateContact(ctx, *c); err != nil {
+		return domain.Contact{}, fmt.Errorf("update contact: %w", err)
+	}
+	return *c, nil
+}
+
+// GetContact returns a contact by ID.
+func (s *ContactService) GetContact(ctx context.Context, id uuid.UUID) (*domain.Contact, error) {
+	return s.repo.GetContact(ctx, id)
+}
+
+// ListContacts returns a page of contacts, optionally of one list.
+func (s *ContactService) ListContacts(ctx context.Context, filter ports.ContactFilter) ([]domain.Contact, error) {
+	return s.repo.ListContacts(ctx, filter)
+}
+
+// DeleteContact removes a contact from the address book and all lists.
+func (s *ContactService) DeleteContact(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteContact(ctx, id)
+}
+
+// CreateList stores a new contact list.
+func (s *ContactService) CreateList(ctx context.Context, name, description string) (domain.ContactList, error) {
+	now := time.Now().UTC()
+	l := domain.ContactList{ID: uuid.New(), Name: name, Description: description, CreatedAt: now, UpdatedAt: now}
+	if err := s.repo.SaveList(ctx, l); err != nil {
+		return domain.ContactList{}, fmt.Errorf("save list: %w", err)
+	}
+	return l, nil
+}
+
+// UpdateList renames a contact list.
+func (s *ContactService) UpdateList(ctx context.Context, id uuid.UUID, name, description string) (*domain.ContactList, error) {
+	if err := s.repo.UpdateList(ctx, domain.ContactList{ID: id, Name: name, Description: description}); err != nil {
+		return nil, err
+	}
+	return s.repo.GetList(ctx, id)
+}
+
+// GetList returns a list with its member count.
+func (s *ContactService) GetList(ctx context.Context, id uuid.UUID) (*domain.ContactList, error) {
+	return s.repo.GetList(ctx, id)
+}
+
+// ListLists returns all contact lists.
+func (s *ContactService) ListLists(ctx context.Context) ([]domain.ContactList, error) {
+	return s.repo.ListLists(ctx)
+}
+
+// DeleteList removes a list; its contacts are kept.
+func (s *ContactService) DeleteList(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteList(ctx, id)
+}
+
+// AddListMembers adds existing contacts to a list.
+func (s *ContactService) AddListMembers(ctx context.Context, listID uuid.UUID, contactIDs []uuid.UUID) error {
+	if _, err := s.repo.GetList(ctx, listID); err != nil {
+		return err
+	}
+	return s.repo.AddListMembers(ctx, listID, contactIDs)
+}
+
+// RemoveListMembers removes contacts from a list.
+func (s *ContactService) RemoveListMembers(ctx context.Context, listID uuid.UUID, contactIDs []uuid.UUID) error {
+	if _, err := s.repo.GetList(ctx, listID); err != nil {
+		return err
+	}
+	return s.repo.RemoveListMembers(ctx, listID, contactIDs)
+}
+
+// ImportContactsRequest is the input for a bulk contact import.
+type ImportContactsRequest struct {
+	CSV         io.Reader
+	PhoneColumn string     // Defaults to "phone"; every other column becomes an attribute
+	ListID      *uuid.UUID // Optional list the imported contacts are added to
+}
+
+// ImportContactsResult describes the outcome of ImportContacts.
+type ImportContactsResult struct {
+	Imported     int
+	Rejected     int
+	RejectedRows []RejectedRow
+}
+
+// ImportContacts streams a CSV into the address book. Existing contacts
+// (matched by phone) keep their attributes, overwritten by non-empty columns.
+// Rows are committed in chunks, so a failure part way keeps earlier chunks.
+func (s *ContactService) ImportContacts(ctx context.Context, req ImportContactsRequest) (ImportContactsResult, error) {
+	if req.ListID != nil {
+		if _, err := s.repo.GetList(ctx, *req.ListID); err != nil {
+			return ImportContactsResult{}, err
+		}
+	}
+
+	reader := csv.NewReader(req.CSV)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return ImportContactsResult{}, fmt.Errorf("%w: file is empty", domain.ErrInvalidUpload)
+	}
+	if err != nil {
+		return ImportContactsResult{}, fmt.Errorf("%w: read header: %v", domain.ErrInvalidUpload, err)
+	}
+	columns := indexColumns(header)
+
+	phoneColumn := req.PhoneColumn
+	if phoneColumn == "" {
+		phoneColumn = "phone"
+	}
+	phoneIdx, ok := columns[strings.ToLower(phoneColumn)]
+	if !ok {
+		return ImportContactsResult{}, fmt.Errorf("%w: no %q column", domain.ErrInvalidUpload, phoneColumn)
+	}
+
+	names := make([]string, len(header))
+	for name, i := range columns {
+		names[i] = name
+	}
+
+	var result ImportContactsResult
+	reject := func(line int, err error) {
+		result.Rejected++
+		if len(result.RejectedRows) < maxReportedRejections {
+			result.RejectedRows = append(result.RejectedRows, RejectedRow{Line: line, Error: err.Error()})
+		}
+	}
+
+	chunk := make([]domain.Contact, 0, importChunkSize)
+	positions := make(map[string]int, importChunkSize) // Phone → index in chunk
+	flush := func() error {
+		ids, err := s.repo.UpsertContacts(ctx, chunk)
+		if err != nil {
+			return fmt.Errorf("upsert contacts: %w", err)
+		}
+		if req.ListID != nil {
+			if err := s.repo.AddListMembers(ctx, *req.ListID, ids); err != nil {
+				return fmt.Errorf("add list members: %w", err)
+			}
+		}
+		result.Imported += len(chunk)
+		chunk = chunk[:0]
+		clear(positions)
+		return nil
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return result, fmt.Errorf("read csv: %w", err)
+			}
+			reject(parseErr.StartLine, parseErr.Err)
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		phone, err := domain.NormalizePhone(record[phoneIdx])
+		if err != nil {
+			reject(line, err)
+			continue
+		}
+
+		attributes := make(map[string]string, len(record)-1)
+		for i, v := range record {
+			if v = strings.TrimSpace(v); i != phoneIdx && v != "" && names[i] != "" {
+				attributes[names[i]] = v
+			}
+		}
+
+		// The same phone twice in one statement cannot be upserted; merge instead.
+		if i, dup := positions[phone]; dup {
+			for k, v := range attributes {
+				chunk[i].Attributes[k] = v
+			}
+			continue
+		}
+		positions[phone] = len(chunk)
+		chunk = append(chunk, domain.Contact{Phone: phone, Attributes: attributes})
+
+		if len(chunk) == importChunkSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if len(chunk) > 0 {
+		if err := flush(); err != nil {
+			return result, err
+		}
+	}
+
+	s.log.Info("contacts imported", "imported", result.Imported, "rejected", result.Rejected, "list_id", req.ListID)
+	return result, nil
+}
+
+// segmentSampleSize is how many matching contacts a segment preview returns.
+const segmentSampleSize = 10
+
+// SegmentPreview is the audience a segment would target.
+type SegmentPreview struct {
+	Count  int64
+	Sample []domain.Contact
+}
+
+// PreviewSegment counts the contacts matching a segment expression, within
+// the given lists when any, and returns a sample of them.
+func (s *ContactService) PreviewSegment(ctx context.Context, segment string, listIDs []uuid.UUID) (SegmentPreview, error) {
+	seg, err := domain.ParseSegment(segment)
+	if err != nil {
+		return SegmentPreview{}, err
+	}
+	audience := ports.Audience{ListIDs: listIDs, Segment: seg}
+
+	count, err := s.repo.CountAudience(ctx, audience)
+	if err != nil {
+		return SegmentPreview{}, fmt.Errorf("count segment: %w", err)
+	}
+
+	sample, err := s.repo.AudienceContacts(ctx, audience, segmentSampleSize)
+	if err != nil {
+		return SegmentPreview{}, fmt.Errorf("sample segment: %w", err)
+	}
+
+	return SegmentPreview{Count: count, Sample: sample}, nil
+}
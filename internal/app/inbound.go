@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// autoReplyBroadcast names the daily system broadcast holding keyword replies.
+const autoReplyBroadcast = "Auto-replies"
+
+// InboundService ingests mobile-originated messages: the webhook enqueues them
+// and the dlr-processor stores them and applies keyword rules.
+type InboundService struct {
+	repo         ports.InboundRepository
+	messages     ports.MessageRepository
+	suppressions ports.SuppressionRepository
+	queue        ports.InboundPublisher
+	rules        []domain.KeywordRule
+	log          *slog.Logger
+}
+
+// NewInboundService wires the service with its dependencies. rules are the
+// keyword rules applied to every inbound message, first match wins.
+func NewInboundService(
+	repo ports.InboundRepository,
+	messages ports.MessageRepository,
+	suppressions ports.SuppressionRepository,
+	queue ports.InboundPublisher,
+	rules []domain.KeywordRule,
+	log *slog.Logger,
+) *InboundService {
+	return &InboundService{
+		repo:         repo,
+		messages:     messages,
+		suppressions: suppressions,
+		queue:        queue,
+		rules:        rules,
+		log:          log,
+	}
+}
+
+// AcceptInbound validates a parsed inbound message and durably enqueues it.
+func (s *InboundService) AcceptInbound(ctx context.Context, msg ports.InboundMessage) error {
+	from, err := domain.NormalizePhone(msg.From)
+	if err != nil {
+		return err
+	}
+	msg.From = from
+
+	if err := s.queue.PublishInbound(ctx, msg); err != nil {
+		return fmt.Errorf("enqueue inbound message: %w", err)
+	}
+
+	s.log.Info("inbound message accepted", "provider", msg.Provider, "provider_id", msg.ProviderID, "from", msg.From)
+	return nil
+}
+
+// HandleInbound stores an inbound message, linked to the most recent message
+// sent to the same number, and applies the first keyword rule it matches: opt-outs and opt-ins update the global suppression list, and the
+// rule's reply is queued through the outbox. Redelivered messages that were
+// already processed are skipped.
+func (s *InboundService) HandleInbound(ctx context.Context, msg ports.InboundMessage) error {
+	now := time.Now().UTC()
+	receivedAt := msg.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = now
+	}
+
+	// Thread the reply onto the last message we sent to the number.
+	var messageID *uuid.UUID
+	last, err := s.repo.LastOutboundMessage(ctx, msg.From, receivedAt)
+	switch {
+	case err == nil:
+		messageID = &last.ID
+	case !errors.Is(err, domain.ErrMessageNotFound):
+		return fmt.Errorf("link inbound message: %w", err)
+	}
+
+	stored, err := s.repo.SaveInbound(ctx, domain.InboundMessage{
+		ID:         uuid.New(),
+		Provider:   msg.Provider,
+		ProviderID: msg.ProviderID,
+		From:       msg.From,
+		To:         msg.To,
+		Body:       msg.Body,
+		ReceivedAt: receivedAt.UTC(),
+		MessageID:  messageID,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return fmt.Errorf("save inbound message: %w", err)
+	}
+	if stored.ProcessedAt != nil {
+		s.log.Info("inbound message already processed", "inbound_id", stored.ID, "provider_id", stored.ProviderID)
+		return nil
+	}
+
+	rule, keyword := domain.MatchKeyword(s.rules, stored.Body)
+	var action domain.KeywordAction
+	if rule != nil {
+		action = rule.Action
+		if err := s.applyKeyword(ctx, *stored, *rule, keyword); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.MarkInboundProcessed(ctx, stored.ID, keyword, action); err != nil {
+		return err
+	}
+
+	s.log.Info("inbound message processed", "inbound_id", stored.ID, "from", stored.From, "keyword", keyword, "action", action)
+	return nil
+}
+
+// applyKeyword runs a matched rule's action and queues its reply.
+func (s *InboundService) applyKeyword(ctx context.Context, msg domain.InboundMessage, rule domain.KeywordRule, keyword string) error {
+	switch rule.Action {
+	case domain.KeywordOptOut:
+		_, err := s.suppressions.AddSuppressions(ctx, []domain.Suppression{{
+			Phone:     msg.From,
+			Reason:    "replied " + keyword,
+			Source:    domain.SuppressionSourceKeyword,
+			CreatedAt: time.Now().UTC(),
+		}})
+		if err != nil {
+			return fmt.Errorf("suppress number: %w", err)
+		}
+
+	case domain.KeywordOptIn:
+		err := s.suppressions.RemoveSuppression(ctx, msg.From, "")
+		if err != nil && !errors.Is(err, domain.ErrSuppressionNotFound) {
+			return fmt.Errorf("unsuppress number: %w", err)
+		}
+	}
+
+	if rule.Reply == "" {
+		return nil
+	}
+
+	// The reply is created after the suppression above, so the sender-worker's
+	// final opt-out check lets the STOP confirmation through.
+	reply := domain.SystemBroadcast(autoReplyBroadcast, time.Now())
+	return s.messages.WithinTx(ctx, func(repo ports.MessageRepository) error {
+		if err := repo.EnsureBroadcast(ctx, reply); err != nil {
+			return err
+		}
+		// Answer from the number or sender ID the message was sent to.
+		answer := domain.NewMessage(reply.ID, msg.From, rule.Reply)
+		answer.Sender = msg.To
+		if err := repo.SaveMessages(ctx, []domain.Message{answer}); err != nil {
+			return fmt.Errorf("save auto-reply: %w", err)
+		}
+		return nil
+	})
+}
+
+// Conversation returns the thread with a phone number in chronological order:
+// the newest limit messages in both directions sent before the given time
+// (now when zero).
+func (s *InboundService) Conversation(ctx context.Context, phone string, before time.Time, limit int) ([]domain.ConversationEntry, error) {
+	phone, err := domain.NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	if before.IsZero() {
+		before = time.Now().UTC()
+	}
+
+	entries, err := s.repo.Conversation(ctx, phone, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get conversation: %w", err)
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
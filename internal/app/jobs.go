@@ -0,0 +1,326 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// JobService runs broadcast creation in the background for recipient lists
+// too large to insert within an HTTP request.
+type JobService struct {
+	jobs       ports.JobRepository
+	broadcasts *BroadcastService
+	staleAfter time.Duration
+	log        *slog.Logger
+}
+
+// NewJobService wires the service with its dependencies.
+func NewJobService(
+	jobs ports.JobRepository,
+	broadcasts *BroadcastService,
+	staleAfter time.Duration,
+	log *slog.Logger,
+) *JobService {
+	return &JobService{
+		jobs:       jobs,
+		broadcasts: broadcasts,
+		staleAfter: staleAfter,
+		log:        log,
+	}
+}
+
+// SubmitBroadcast queues a job for a JSON broadcast request. The recipients
+// are stored as a one-column CSV so both entry points share one import path;
+// contact lists and the segment are expanded when the job runs.
+func (s *JobService) SubmitBroadcast(ctx context.Context, req CreateBroadcastRequest) (domain.BroadcastJob, error) {
+	totalRows := len(req.Recipient)
+	if len(req.ListIDs) > 0 || req.Segment != "" {
+		// Fail fast on unknown lists or a bad segment; the count also sizes
+		// the job's progress.
+		audience, err := parseAudience(req)
+		if err != nil {
+			return domain.BroadcastJob{}, err
+		}
+		n, err := s.broadcasts.contacts.CountAudience(ctx, audience)
+		if err != nil {
+			return domain.BroadcastJob{}, fmt.Errorf("count audience: %w", err)
+		}
+		totalRows += int(n)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"phone"})
+	for _, to := range req.Recipient {
+		_ = w.Write([]string{to})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return domain.BroadcastJob{}, fmt.Errorf("encode recipients: %w", err)
+	}
+
+	job := newJob(UploadBroadcastRequest{BroadcastOptions: req.BroadcastOptions}, totalRows)
+	job.ListIDs = req.ListIDs
+	job.Segment = req.Segment
+
+	return s.submit(ctx, job, &buf)
+}
+
+// SubmitUpload queues a job for a CSV upload. The file is read twice, to
+// count its rows and to store it, so req.CSV must be seekable; the HTTP
+// handler spools uploads to a temporary file.
+func (s *JobService) SubmitUpload(ctx context.Context, req UploadBroadcastRequest) (domain.BroadcastJob, error) {
+	file, ok := req.CSV.(io.ReadSeeker)
+	if !ok {
+		return domain.BroadcastJob{}, errors.New("submit upload: csv is not seekable")
+	}
+
+	rows := countRows(file)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return domain.BroadcastJob{}, fmt.Errorf("rewind upload: %w", err)
+	}
+
+	return s.submit(ctx, newJob(req, rows), file)
+}
+
+func (s *JobService) submit(ctx context.Context, job domain.BroadcastJob, input io.Reader) (domain.BroadcastJob, error) {
+	// Fail fast on an unknown sender; the job checks it again when it runs.
+	if _, err := s.broadcasts.resolveSender(ctx, job.Sender); err != nil {
+		return domain.BroadcastJob{}, err
+	}
+
+	if err := s.jobs.SaveJob(ctx, job, input); err != nil {
+		return domain.BroadcastJob{}, fmt.Errorf("save job: %w", err)
+	}
+
+	s.log.Info("broadcast job queued", "job_id", job.ID, "rows", job.TotalRows)
+	return job, nil
+}
+
+// newJob returns a queued job for req whose input has totalRows recipients.
+func newJob(req UploadBroadcastRequest, totalRows int) domain.BroadcastJob {
+	now := time.Now().UTC()
+	return domain.BroadcastJob{
+		ID:               uuid.New(),
+		Status:           domain.JobQueued,
+		BroadcastOptions: req.BroadcastOptions,
+		PhoneColumn:      req.PhoneColumn,
+		TotalRows:        totalRows,
+		Errors:           "[]",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// RunNext claims one job and expands its recipients into the outbox.
+// It reports whether a job was found. This is called by the job-worker binary.
+func (s *JobService) RunNext(ctx context.Context) (bool, error) {
+	job, err := s.jobs.ClaimJob(ctx, s.staleAfter)
+	if err != nil {
+		return false, fmt.Errorf("claim job: %w", err)
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	s.log.Info("broadcast job started", "job_id", job.ID, "rows", job.TotalRows)
+
+	broadcastID := domain.JobBroadcastID(job.ID)
+	if done, err := s.finishIfCreated(ctx, job, broadcastID); done || err != nil {
+		return true, err
+	}
+
+	result, err := s.createBroadcast(ctx, job, broadcastID)
+	if err != nil && ctx.Err() != nil {
+		// Shutting down: the transaction rolled back and the job is picked
+		// up again once it goes stale.
+		return true, ctx.Err()
+	}
+
+	job.Queued, job.Rejected, job.Suppressed = result.Queued, result.Rejected, result.Suppressed
+	job.Capped, job.Duplicates = result.Capped, result.Duplicates
+	if rows, mErr := json.Marshal(result.RejectedRows); mErr == nil && result.RejectedRows != nil {
+		job.Errors = string(rows)
+	}
+
+	switch {
+	case err == nil:
+		job.Status = domain.JobCompleted
+		job.BroadcastID = &result.BroadcastID
+	case errors.Is(err, domain.ErrInvalidUpload), errors.Is(err, domain.ErrNoRecipients), errors.Is(err, domain.ErrSenderNotFound),
+		errors.Is(err, domain.ErrListNotFound), errors.Is(err, domain.ErrInvalidSegment):
+		job.Status = domain.JobFailed
+		job.Error = err.Error()
+	default:
+		// Another run of the job may have committed the broadcast meanwhile,
+		// making our insert conflict with it.
+		if done, err := s.finishIfCreated(ctx, job, broadcastID); done || err != nil {
+			return true, err
+		}
+		s.log.Error("broadcast job failed", "job_id", job.ID, "err", err)
+		job.Status = domain.JobFailed
+		job.Error = "internal error"
+	}
+
+	if err := s.jobs.FinishJob(ctx, *job); err != nil {
+		return true, fmt.Errorf("finish job: %w", err)
+	}
+
+	s.log.Info("broadcast job finished", "job_id", job.ID, "status", job.Status, "queued", job.Queued, "rejected", job.Rejected, "suppressed", job.Suppressed, "capped", job.Capped, "duplicates", job.Duplicates)
+	return true, nil
+}
+
+// createBroadcast imports a job's input. A job with contact lists or a
+// segment is created like a JSON request, its recipients read back from the
+// input; any other job streams its input through the CSV import.
+func (s *JobService) createBroadcast(ctx context.Context, job *domain.BroadcastJob, broadcastID uuid.UUID) (UploadBroadcastResult, error) {
+	if len(job.ListIDs) == 0 && job.Segment == "" {
+		return s.broadcasts.CreateBroadcastFromCSV(ctx, UploadBroadcastRequest{
+			BroadcastOptions: job.BroadcastOptions,
+			BroadcastID:      broadcastID,
+			PhoneColumn:      job.PhoneColumn,
+			CSV:              s.jobs.JobInput(ctx, job.ID),
+			Progress: func(progress UploadBroadcastResult) {
+				job.Queued, job.Rejected, job.Suppressed = progress.Queued, progress.Rejected, progress.Suppressed
+				job.Capped, job.Duplicates = progress.Capped, progress.Duplicates
+				if err := s.jobs.UpdateJobProgress(ctx, *job); err != nil {
+					s.log.Error("update job progress failed", "job_id", job.ID, "err", err)
+				}
+			},
+		})
+	}
+
+	// Expanding the audience reports no progress, so keep the job from
+	// going stale while it runs.
+	stop := s.keepAlive(ctx, job.ID)
+	defer stop()
+
+	recipients, err := readRecipients(s.jobs.JobInput(ctx, job.ID))
+	if err != nil {
+		return UploadBroadcastResult{}, err
+	}
+
+	result, err := s.broadcasts.CreateBroadcast(ctx, CreateBroadcastRequest{
+		BroadcastOptions: job.BroadcastOptions,
+		BroadcastID:      broadcastID,
+		Recipient:        recipients,
+		ListIDs:          job.ListIDs,
+		Segment:          job.Segment,
+	})
+	return UploadBroadcastResult{CreateBroadcastResult: result, Rejected: result.Rejected}, err
+}
+
+// keepAlive touches the job every third of staleAfter until stop is called,
+// so other workers do not claim it while a long step runs.
+func (s *JobService) keepAlive(ctx context.Context, id uuid.UUID) (stop func()) {
+	if s.staleAfter <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.staleAfter / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.jobs.TouchJob(ctx, id); err != nil && ctx.Err() == nil {
+					s.log.Error("touch job failed", "job_id", id, "err", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// finishIfCreated completes a job whose broadcast an earlier run already
+// committed, taking the counts stored with the broadcast. It reports whether
+// the job was finished.
+func (s *JobService) finishIfCreated(ctx context.Context, job *domain.BroadcastJob, broadcastID uuid.UUID) (bool, error) {
+	broadcast, err := s.broadcasts.repo.FindBroadcast(ctx, broadcastID)
+	if errors.Is(err, domain.ErrBroadcastNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("find job broadcast: %w", err)
+	}
+
+	job.Status = domain.JobCompleted
+	job.BroadcastID = &broadcastID
+	job.Queued, job.Rejected, job.Suppressed = broadcast.Queued, broadcast.Rejected, broadcast.Suppressed
+	job.Capped, job.Duplicates = broadcast.Capped, broadcast.Duplicates
+	job.Error = ""
+	if err := s.jobs.FinishJob(ctx, *job); err != nil {
+		return false, fmt.Errorf("finish job: %w", err)
+	}
+
+	s.log.Info("broadcast job already created", "job_id", job.ID, "broadcast_id", broadcastID)
+	return true, nil
+}
+
+// GetJob returns a job's progress and validation errors.
+func (s *JobService) GetJob(ctx context.Context, id uuid.UUID) (*domain.BroadcastJob, error) {
+	return s.jobs.GetJob(ctx, id)
+}
+
+// readRecipients returns the numbers of a one-column CSV written by
+// SubmitBroadcast.
+func readRecipients(input io.Reader) ([]string, error) {
+	r := csv.NewReader(input)
+	if _, err := r.Read(); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("read recipients: %w", err)
+	}
+
+	var recipients []string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return recipients, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read recipients: %w", err)
+		}
+		recipients = append(recipients, record[0])
+	}
+}
+
+// countRows returns the number of data rows in a CSV file, for progress reporting.
+func countRows(input io.Reader) int {
+	r := csv.NewReader(input)
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+
+	rows := -1 // Header
+	for {
+		_, err := r.Read()
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			break // io.EOF, or the input could not be read
+		}
+		rows++
+	}
+	if rows < 0 {
+		return 0
+	}
+	return rows
+}
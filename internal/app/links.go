@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+// LinkService resolves the short links rewritten into message bodies and
+// records who clicked them.
+type LinkService struct {
+	repo ports.LinkRepository
+	log  *slog.Logger
+}
+
+// NewLinkService wires the service with its dependencies.
+func NewLinkService(repo ports.LinkRepository, log *slog.Logger) *LinkService {
+	return &LinkService{repo: repo, log: log}
+}
+
+// Resolve returns the URL a short link redirects to. With record set the
+// visit is counted as a click on the link's message. A failure to record is
+// logged only: the recipient still reaches the page.
+func (s *LinkService) Resolve(ctx context.Context, code, userAgent string, record bool) (string, error) {
+	if !domain.ValidShortCode(code) {
+		return "", domain.ErrLinkNotFound
+	}
+
+	link, err := s.repo.GetShortLink(ctx, code)
+	if err != nil {
+		return "", err
+	}
+
+	if record {
+		err := s.repo.RecordClick(ctx, domain.LinkClick{
+			Code:        link.Code,
+			MessageID:   link.MessageID,
+			BroadcastID: link.BroadcastID,
+			UserAgent:   userAgent,
+			ClickedAt:   time.Now().UTC(),
+		})
+		if err != nil {
+			s.log.Error("record click failed", "code", code, "msg_id", link.MessageID, "err", err)
+		}
+	}
+
+	return link.URL, nil
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// otpBroadcast names the daily system broadcast holding OTP messages.
+	otpBroadcast = "One-time passwords"
+
+	// defaultOTPBody is the message text when the request sets none.
+	defaultOTPBody = "Your verification code is {{code}}"
+)
+
+// OTPService sends one-time passwords and verifies the codes users enter.
+type OTPService struct {
+	otps       ports.OTPRepository
+	broadcasts *BroadcastService
+	policy     domain.OTPPolicy
+	secret     []byte
+	log        *slog.Logger
+}
+
+// NewOTPService wires the service with its dependencies. secret keys the
+// stored code hashes.
+func NewOTPService(
+	otps ports.OTPRepository,
+	broadcasts *BroadcastService,
+	policy domain.OTPPolicy,
+	secret string,
+	log *slog.Logger,
+) *OTPService {
+	return &OTPService{
+		otps:       otps,
+		broadcasts: broadcasts,
+		policy:     policy,
+		secret:     []byte(secret),
+		log:        log,
+	}
+}
+
+// SendOTPRequest is the input for SendOTP.
+type SendOTPRequest struct {
+	Phone  string
+	Sender string // Registered sender ID; empty uses the provider's default
+	Body   string // Must contain {{code}}; empty uses defaultOTPBody
+}
+
+// SendOTP generates a code, stores its hash and sends it on the
+// high-priority lane. The number's earlier codes stop working.
+func (s *OTPService) SendOTP(ctx context.Context, req SendOTPRequest) (domain.OTP, error) {
+	phone, err := domain.NormalizePhone(req.Phone)
+	if err != nil {
+		return domain.OTP{}, err
+	}
+
+	body := req.Body
+	if body == "" {
+		body = defaultOTPBody
+	}
+	if !slices.Contains(domain.TemplateVariables(body), domain.OTPCodePlaceholder) {
+		return domain.OTP{}, domain.ErrInvalidOTPBody
+	}
+
+	now := time.Now().UTC()
+	if err := s.checkLockout(ctx, phone, now); err != nil {
+		return domain.OTP{}, err
+	}
+
+	code, err := domain.GenerateOTPCode(s.policy.CodeLength)
+	if err != nil {
+		return domain.OTP{}, fmt.Errorf("generate otp: %w", err)
+	}
+	text, err := domain.RenderTemplate(body, map[string]string{domain.OTPCodePlaceholder: code})
+	if err != nil {
+		return domain.OTP{}, fmt.Errorf("%w: %v", domain.ErrInvalidOTPBody, err)
+	}
+
+	otp := domain.OTP{
+		ID:          uuid.New(),
+		Phone:       phone,
+		Status:      domain.OTPPending,
+		MaxAttempts: s.policy.MaxAttempts,
+		ExpiresAt:   now.Add(s.policy.TTL),
+		CreatedAt:   now,
+	}
+	otp.CodeHash = domain.HashOTPCode(s.secret, otp.ID, code)
+
+	// The OTP is stored first so the code verifies as soon as it arrives.
+	// If the message cannot be queued the OTP is never attached to one and
+	// simply expires; it still counts towards the resend interval.
+	saved, err := s.otps.SaveOTP(ctx, otp, now.Add(-s.policy.ResendInterval))
+	if err != nil {
+		return domain.OTP{}, fmt.Errorf("save otp: %w", err)
+	}
+	if !saved {
+		return domain.OTP{}, domain.ErrOTPTooSoon
+	}
+
+	msg, err := s.broadcasts.QueueSystemMessage(ctx, SystemMessageRequest{
+		Broadcast: otpBroadcast,
+		To:        phone,
+		Sender:    req.Sender,
+		Body:      text,
+		Priority:  domain.PriorityHigh,
+	})
+	if err != nil {
+		return domain.OTP{}, err
+	}
+
+	if err := s.otps.AttachOTPMessage(ctx, otp.ID, msg.ID); err != nil {
+		return domain.OTP{}, fmt.Errorf("attach otp message: %w", err)
+	}
+	otp.MessageID = &msg.ID
+
+	s.log.Info("otp sent", "otp_id", otp.ID, "msg_id", msg.ID, "to", phone)
+	return otp, nil
+}
+
+// VerifyOTP checks a code. Every call counts as an attempt: an OTP locks
+// after MaxAttempts wrong codes, and a number is locked out of sending and
+// verifying after LockoutAttempts wrong codes within LockoutWindow.
+func (s *OTPService) VerifyOTP(ctx context.Context, id uuid.UUID, code string) (domain.OTP, error) {
+	otp, err := s.otps.GetOTP(ctx, id)
+	if err != nil {
+		return domain.OTP{}, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.checkLockout(ctx, otp.Phone, now); err != nil {
+		return domain.OTP{}, err
+	}
+	if err := otpStateError(otp, now); err != nil {
+		return domain.OTP{}, err
+	}
+
+	attempts, counted, err := s.otps.RecordOTPAttempt(ctx, id, now)
+	if err != nil {
+		return domain.OTP{}, err
+	}
+	if !counted {
+		// A concurrent attempt used up, verified or expired the OTP.
+		if otp, err = s.otps.GetOTP(ctx, id); err != nil {
+			return domain.OTP{}, err
+		}
+		if err := otpStateError(otp, now); err != nil {
+			return domain.OTP{}, err
+		}
+		return domain.OTP{}, domain.ErrOTPLocked
+	}
+
+	if otp.CheckOTPCode(s.secret, code) {
+		verified, err := s.otps.FinishOTP(ctx, id, domain.OTPVerified, now)
+		if err != nil {
+			return domain.OTP{}, err
+		}
+		if !verified {
+			return domain.OTP{}, domain.ErrOTPUsed
+		}
+
+		otp.Status, otp.Attempts, otp.VerifiedAt = domain.OTPVerified, attempts, &now
+		s.log.Info("otp verified", "otp_id", id, "attempts", attempts)
+		return otp, nil
+	}
+
+	if attempts >= otp.MaxAttempts {
+		if _, err := s.otps.FinishOTP(ctx, id, domain.OTPLocked, now); err != nil {
+			return domain.OTP{}, err
+		}
+		s.log.Warn("otp locked", "otp_id", id, "phone", otp.Phone)
+		return domain.OTP{}, domain.ErrOTPLocked
+	}
+	return domain.OTP{}, fmt.Errorf("%w: %d attempts left", domain.ErrOTPMismatch, otp.MaxAttempts-attempts)
+}
+
+// checkLockout refuses numbers with too many recent wrong codes.
+func (s *OTPService) checkLockout(ctx context.Context, phone string, now time.Time) error {
+	if s.policy.LockoutAttempts <= 0 {
+		return nil
+	}
+
+	failed, err := s.otps.FailedOTPAttempts(ctx, phone, now.Add(-s.policy.LockoutWindow))
+	if err != nil {
+		return err
+	}
+	if failed >= s.policy.LockoutAttempts {
+		s.log.Warn("otp lockout", "phone", phone, "failed", failed)
+		return domain.ErrOTPLocked
+	}
+	return nil
+}
+
+// otpStateError explains why an OTP can no longer be verified, or returns
+// nil while it can.
+func otpStateError(otp domain.OTP, now time.Time) error {
+	switch {
+	case otp.Status == domain.OTPVerified:
+		return domain.ErrOTPUsed
+	case otp.Status == domain.OTPLocked:
+		return domain.ErrOTPLocked
+	case otp.Status == domain.OTPReplaced, !now.Before(otp.ExpiresAt):
+		return domain.ErrOTPExpired
+	case otp.Status != domain.OTPPending:
+		return fmt.Errorf("unknown otp status %q", otp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+// ReceiptService ingests delivery receipts: the webhook enqueues them and the
+// dlr-processor applies them to messages in batches.
+type ReceiptService struct {
+	repo       ports.MessageRepository
+	queue      ports.DLRPublisher
+	pendingTTL time.Duration
+	log        *slog.Logger
+}
+
+// NewReceiptService wires the service with its dependencies. pendingTTL is how
+// long a receipt that matches no message is parked before it is purged.
+func NewReceiptService(
+	repo ports.MessageRepository,
+	queue ports.DLRPublisher,
+	pendingTTL time.Duration,
+	log *slog.Logger,
+) *ReceiptService {
+	return &ReceiptService{
+		repo:       repo,
+		queue:      queue,
+		pendingTTL: pendingTTL,
+		log:        log,
+	}
+}
+
+// AcceptDLR validates a parsed receipt and durably enqueues it.
+// Receipts whose status is outside the domain set are rejected.
+func (s *ReceiptService) AcceptDLR(ctx context.Context, dlr ports.DLRPayload) error {
+	if !dlr.Status.IsValid() {
+		return fmt.Errorf("dlr status %q: %w", dlr.Status, domain.ErrUnknownStatus)
+	}
+
+	if err := s.queue.PublishDLR(ctx, dlr); err != nil {
+		return fmt.Errorf("enqueue dlr: %w", err)
+	}
+
+	s.log.Info("DLR accepted", "provider", dlr.Provider, "provider_id", dlr.ProviderID, "status", dlr.Status)
+	return nil
+}
+
+// ApplyDLRBatch writes a batch of receipts in one update. Receipts whose
+// provider ID is not stored yet (the DLR beat SetProviderID) are parked in
+// pending_dlrs and replayed when the ID is attached.
+func (s *ReceiptService) ApplyDLRBatch(ctx context.Context, batch []ports.DLRPayload) error {
+	latest := dedupeReceipts(batch)
+
+	receipts := make([]domain.Receipt, 0, len(latest))
+	for _, dlr := range latest {
+		receipts = append(receipts, toReceipt(dlr))
+	}
+
+	unmatched, err := s.repo.ApplyReceipts(ctx, receipts)
+	if err != nil {
+		return fmt.Errorf("apply receipts: %w", err)
+	}
+
+	s.enqueueCallbacks(ctx, matchedRefs(receipts, unmatched))
+
+	if len(unmatched) > 0 {
+		if err := s.park(ctx, unmatched); err != nil {
+			return err
+		}
+	}
+
+	s.log.Info("DLR batch applied", "received", len(batch), "applied", len(latest)-len(unmatched), "parked", len(unmatched))
+	return nil
+}
+
+// park stores the unmatched receipts and then immediately tries to replay
+// them: the parking insert is committed first, so a SetProviderID that
+// committed in between is either seen here or sees the parked row itself.
+func (s *ReceiptService) park(ctx context.Context, unmatched []domain.Receipt) error {
+	if err := s.repo.ParkReceipts(ctx, unmatched, time.Now().Add(s.pendingTTL)); err != nil {
+		return fmt.Errorf("park receipts: %w", err)
+	}
+
+	refs := make([]domain.ProviderRef, 0, len(unmatched))
+	for _, rc := range unmatched {
+		refs = append(refs, rc.Ref())
+	}
+
+	if n, err := s.repo.ReplayPendingReceipts(ctx, refs); err != nil {
+		// Parked receipts are still replayed by SetProviderID; nothing is lost.
+		s.log.Error("replay parked receipts", "err", err)
+	} else if n > 0 {
+		s.enqueueCallbacks(ctx, refs)
+	}
+	return nil
+}
+
+// enqueueCallbacks queues status callbacks for the given provider references.
+// Failures are logged only: the receipts themselves are already applied.
+func (s *ReceiptService) enqueueCallbacks(ctx context.Context, refs []domain.ProviderRef) {
+	if err := s.repo.EnqueueStatusCallbacksByProviderRef(ctx, refs); err != nil {
+		s.log.Error("enqueue status callbacks failed", "count", len(refs), "err", err)
+	}
+}
+
+// matchedRefs returns the provider references of receipts that are not in unmatched.
+func matchedRefs(receipts, unmatched []domain.Receipt) []domain.ProviderRef {
+	missing := make(map[domain.ProviderRef]bool, len(unmatched))
+	for _, rc := range unmatched {
+		missing[rc.Ref()] = true
+	}
+
+	refs := make([]domain.ProviderRef, 0, len(receipts))
+	for _, rc := range receipts {
+		if !missing[rc.Ref()] {
+			refs = append(refs, rc.Ref())
+		}
+	}
+	return refs
+}
+
+// PurgeExpiredDLRs deletes parked receipts that never matched a message.
+func (s *ReceiptService) PurgeExpiredDLRs(ctx context.Context) (int64, error) {
+	n, err := s.repo.PurgeExpiredReceipts(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("purge expired receipts: %w", err)
+	}
+	if n > 0 {
+		s.log.Warn("purged unmatched DLRs", "count", n)
+	}
+	return n, nil
+}
+
+// dedupeReceipts keeps one receipt per provider and provider ID, preferring
+// the latest final status so duplicates and out-of-order receipts collapse safely.
+func dedupeReceipts(batch []ports.DLRPayload) []ports.DLRPayload {
+	index := make(map[domain.ProviderRef]int, len(batch))
+	out := make([]ports.DLRPayload, 0, len(batch))
+	for _, dlr := range batch {
+		ref := domain.ProviderRef{Provider: dlr.Provider, ProviderID: dlr.ProviderID}
+		i, seen := index[ref]
+		if !seen {
+			index[ref] = len(out)
+			out = append(out, dlr)
+			continue
+		}
+		if dlr.Status.IsFinal() || !out[i].Status.IsFinal() {
+			out[i] = dlr
+		}
+	}
+	return out
+}
+
+// toReceipt converts a parsed DLR into the domain receipt stored on the message.
+func toReceipt(dlr ports.DLRPayload) domain.Receipt {
+	receipt := domain.Receipt{
+		Provider:         dlr.Provider,
+		ProviderID:       dlr.ProviderID,
+		Status:           dlr.Status,
+		ErrorCode:        dlr.ErrorCode,
+		ErrorDescription: dlr.ErrorDescription,
+	}
+	if dlr.Status == domain.StatusDelivered {
+		deliveredAt := dlr.DoneAt
+		if deliveredAt.IsZero() {
+			deliveredAt = time.Now().UTC()
+		}
+		receipt.DeliveredAt = &deliveredAt
+	}
+	return receipt
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+// SenderService manages the registry of sender IDs broadcasts may send from.
+type SenderService struct {
+	repo ports.SenderRepository
+	log  *slog.Logger
+}
+
+// NewSenderService wires the service with its dependencies.
+func NewSenderService(repo ports.SenderRepository, log *slog.Logger) *SenderService {
+	return &SenderService{repo: repo, log: log}
+}
+
+// CreateSender validates and registers a sender ID.
+func (s *SenderService) CreateSender(ctx context.Context, sender domain.SenderID) (domain.SenderID, error) {
+	if err := sender.Validate(); err != nil {
+		return domain.SenderID{}, err
+	}
+
+	now := time.Now().UTC()
+	sender.CreatedAt, sender.UpdatedAt = now, now
+	if err := s.repo.CreateSender(ctx, sender); err != nil {
+		return domain.SenderID{}, fmt.Errorf("save sender: %w", err)
+	}
+
+	s.log.Info("sender registered", "sender", sender.Sender, "type", sender.Type, "countries", sender.CountryCodes)
+	return sender, nil
+}
+
+// UpdateSender replaces the type, country codes and description of a sender.
+// Broadcasts already created keep their recipients.
+func (s *SenderService) UpdateSender(ctx context.Context, sender domain.SenderID) (domain.SenderID, error) {
+	if err := sender.Validate(); err != nil {
+		return domain.SenderID{}, err
+	}
+
+	if err := s.repo.UpdateSender(ctx, sender); err != nil {
+		return domain.SenderID{}, err
+	}
+
+	s.log.Info("sender updated", "sender", sender.Sender, "type", sender.Type, "countries", sender.CountryCodes)
+	return s.repo.GetSender(ctx, sender.Sender)
+}
+
+// GetSender returns a registered sender.
+func (s *SenderService) GetSender(ctx context.Context, sender string) (domain.SenderID, error) {
+	return s.repo.GetSender(ctx, sender)
+}
+
+// ListSenders returns all registered senders.
+func (s *SenderService) ListSenders(ctx context.Context) ([]domain.SenderID, error) {
+	return s.repo.ListSenders(ctx)
+}
+
+// DeleteSender unregisters a sender; new broadcasts can no longer use it.
+func (s *SenderService) DeleteSender(ctx context.Context, sender string) error {
+	if err := s.repo.DeleteSender(ctx, sender); err != nil {
+		return err
+	}
+
+	s.log.Info("sender removed", "sender", sender)
+	return nil
+}
@@ -2,66 +2,745 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"golang-sms-broadcast/internal/domain"
 	"golang-sms-broadcast/internal/ports"
+
+	"github.com/google/uuid"
 )
 
 // BroadcastService is the central application service that orchestrates
 // creating broadcasts, dispatching messages, and handling delivery receipts.
 type BroadcastService struct {
-	repo      ports.MessageRepository
-	publisher ports.MessagePublisher
-	provider  ports.SMSProvider
-	log       *slog.Logger
+	repo           ports.MessageRepository
+	contacts       ports.ContactRepository
+	suppressions   ports.SuppressionRepository
+	senders        ports.SenderRepository
+	publisher      ports.MessagePublisher
+	provider       ports.SMSProvider
+	idempotencyTTL time.Duration
+	frequencyCap   domain.FrequencyCap
+	shortLinkBase  string
+	log            *slog.Logger
 }
 
-// NewBroadcastService wires the service with its dependencies.
+// NewBroadcastService wires the service with its dependencies. frequencyCap
+// limits marketing messages per number; its zero value disables capping.
+// shortLinkBase is the URL short links are served under, e.g.
+// "https://s.example.com"; empty disables link shortening.
 func NewBroadcastService(
 	repo ports.MessageRepository,
+	contacts ports.ContactRepository,
+	suppressions ports.SuppressionRepository,
+	senders ports.SenderRepository,
 	publisher ports.MessagePublisher,
 	provider ports.SMSProvider,
+	idempotencyTTL time.Duration,
+	frequencyCap domain.FrequencyCap,
+	shortLinkBase string,
 	log *slog.Logger,
 ) *BroadcastService {
 	return &BroadcastService{
-		repo:      repo,
-		publisher: publisher,
-		provider:  provider,
-		log:       log,
+		repo:           repo,
+		contacts:       contacts,
+		suppressions:   suppressions,
+		senders:        senders,
+		publisher:      publisher,
+		provider:       provider,
+		idempotencyTTL: idempotencyTTL,
+		frequencyCap:   frequencyCap,
+		shortLinkBase:  shortLinkBase,
+		log:            log,
 	}
 }
 
 // CreateBroadcastRequest is the input for creating a new SMS broadcast.
 type CreateBroadcastRequest struct {
-	Name      string
-	Body      string
+	domain.BroadcastOptions
+
 	Recipient []string
+
+	// ListIDs are contact lists expanded into messages at creation time.
+	// For list contacts, {{attribute}} placeholders in Body are filled from
+	// the contact's attributes; Recipient numbers get Body as is.
+	ListIDs []uuid.UUID
+
+	// Segment is a filter expression over contact attributes, e.g.
+	// city = "Bangkok" AND tier IN ("gold", "platinum"). Alone it targets all
+	// matching contacts; with ListIDs only the matching list members.
+	Segment string
+
+	// IdempotencyKey makes retries safe: a repeated request with the same key
+	// returns the original result instead of creating a second broadcast.
+	IdempotencyKey string
+
+	// BroadcastID, if set, is used instead of a new ID. Jobs pass
+	// domain.JobBroadcastID so that running a job twice cannot create two
+	// broadcasts.
+	BroadcastID uuid.UUID
+}
+
+// CreateBroadcastResult describes the outcome of CreateBroadcast.
+type CreateBroadcastResult struct {
+	BroadcastID uuid.UUID
+	Queued      int
+	Rejected    int  // Recipients skipped for lacking a template attribute, being outside the sender's countries or having no time zone for the window
+	Suppressed  int  // Recipients dropped because they are on the suppression list
+	Capped      int  // Messages saved as suppressed because the recipient reached the frequency cap
+	Duplicates  int  // Recipients skipped because they recently got the same body
+	Replayed    bool // True when answered from a stored idempotency key
 }
 
 // CreateBroadcast persists a Broadcast and its Messages to the outbox.
-func (s *BroadcastService) CreateBroadcast(ctx context.Context, req CreateBroadcastRequest) (domain.Broadcast, error) {
-	broadcast := domain.NewBroadcast(req.Name)
+func (s *BroadcastService) CreateBroadcast(ctx context.Context, req CreateBroadcastRequest) (CreateBroadcastResult, error) {
+	broadcast := newBroadcast(req.BroadcastOptions, req.BroadcastID)
+
+	// The idempotency key, broadcast and messages commit or roll back together,
+	// so a failed attempt leaves nothing behind and the client can simply retry.
+	// The key is claimed first: a retry must not have its recipients filtered
+	// again, or the first attempt's messages would count against it as
+	// duplicates and towards the frequency cap.
+	var result CreateBroadcastResult
+	var recipients int
+	err := s.repo.WithinTx(ctx, func(repo ports.MessageRepository) error {
+		if req.IdempotencyKey != "" {
+			now := time.Now().UTC()
+			existing, err := repo.ClaimIdempotencyKey(ctx, domain.IdempotencyKey{
+				Key:         req.IdempotencyKey,
+				Fingerprint: fingerprint(req),
+				BroadcastID: broadcast.ID,
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(s.idempotencyTTL),
+			})
+			if err != nil {
+				return fmt.Errorf("claim idempotency key: %w", err)
+			}
+
+			if existing != nil {
+				if existing.Fingerprint != fingerprint(req) {
+					return domain.ErrIdempotencyConflict
+				}
+				result = CreateBroadcastResult{
+					BroadcastID: existing.BroadcastID,
+					Queued:      existing.Queued,
+					Rejected:    existing.Rejected,
+					Suppressed:  existing.Suppressed,
+					Capped:      existing.Capped,
+					Duplicates:  existing.Duplicates,
+					Replayed:    true,
+				}
+				return nil
+			}
+		}
+
+		msgs, links, err := s.buildMessages(ctx, repo, broadcast, req, &result)
+		if err != nil {
+			return err
+		}
+		recipients = len(msgs)
+
+		broadcast.Queued, broadcast.Rejected, broadcast.Suppressed = result.Queued, result.Rejected, result.Suppressed
+		broadcast.Capped, broadcast.Duplicates = result.Capped, result.Duplicates
+		if err := repo.SaveBroadcast(ctx, broadcast); err != nil {
+			return fmt.Errorf("save broadcast: %w", err)
+		}
+
+		if err := repo.SaveMessages(ctx, msgs); err != nil {
+			return fmt.Errorf("save messages: %w", err)
+		}
+
+		if err := repo.SaveShortLinks(ctx, links); err != nil {
+			return fmt.Errorf("save short links: %w", err)
+		}
+
+		if req.IdempotencyKey != "" {
+			if err := repo.SaveIdempotencyResult(ctx, domain.IdempotencyKey{
+				Key:        req.IdempotencyKey,
+				Queued:     result.Queued,
+				Rejected:   result.Rejected,
+				Suppressed: result.Suppressed,
+				Capped:     result.Capped,
+				Duplicates: result.Duplicates,
+			}); err != nil {
+				return fmt.Errorf("save idempotency result: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if errors.Is(err, domain.ErrNoRecipients) {
+		return CreateBroadcastResult{Rejected: result.Rejected, Suppressed: result.Suppressed, Duplicates: result.Duplicates}, err
+	}
+	if err != nil {
+		return CreateBroadcastResult{}, err
+	}
+
+	if result.Replayed {
+		s.log.Info("broadcast replayed", "broadcast_id", result.BroadcastID, "idempotency_key", req.IdempotencyKey)
+		return result, nil
+	}
+
+	s.log.Info("broadcast created", "broadcast_id", broadcast.ID, "recipients", recipients, "suppressed", result.Suppressed, "capped", result.Capped, "duplicates", result.Duplicates)
+	return result, nil
+}
+
+// newBroadcast returns the broadcast opts describe, with id unless it is
+// uuid.Nil. Its messages are built by the caller.
+func newBroadcast(opts domain.BroadcastOptions, id uuid.UUID) domain.Broadcast {
+	broadcast := domain.NewBroadcast(opts.Name)
+	if id != uuid.Nil {
+		broadcast.ID = id
+	}
+	broadcast.CallbackURL = opts.CallbackURL
+	broadcast.Window = opts.Window
+	broadcast.Sender = opts.Sender
+	broadcast.ShortenLinks = opts.ShortenLinks
+	broadcast.Variants = opts.Variants
+	if opts.Category != "" {
+		broadcast.Category = opts.Category
+	}
+	return broadcast
+}
+
+// buildMessages expands req into the broadcast's messages and filters them
+// by sender country, time zone, suppression list, dedup window and frequency
+// cap, filling in result's counts. It returns domain.ErrNoRecipients when no
+// message is left to save.
+func (s *BroadcastService) buildMessages(ctx context.Context, repo ports.MessageRepository, broadcast domain.Broadcast, req CreateBroadcastRequest, result *CreateBroadcastResult) ([]domain.Message, []domain.ShortLink, error) {
+	sender, err := s.resolveSender(ctx, req.Sender)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recipients, invalid := normalizeRecipients(req.Recipient)
+	result.Rejected = invalid
+
+	msgs := make([]domain.Message, 0, len(recipients))
+	for _, to := range recipients {
+		variant, body := bodyFor(broadcast, req.Body, to)
+		msg := domain.NewMessage(broadcast.ID, to, body)
+		msg.Variant = variant
+		msgs = append(msgs, msg)
+	}
+
+	if len(req.ListIDs) > 0 || req.Segment != "" {
+		listMsgs, skipped, err := s.expandAudience(ctx, broadcast, req, recipients)
+		if err != nil {
+			return nil, nil, err
+		}
+		msgs = append(msgs, listMsgs...)
+		result.Rejected += skipped
+	}
+
+	msgs, disallowed := dropDisallowed(sender, msgs)
+	result.Rejected += disallowed
+
+	msgs, noZone := dropWithoutZone(broadcast.Window, msgs)
+	result.Rejected += noZone
+
+	msgs, result.Suppressed, err = s.dropSuppressed(ctx, req.Sender, msgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msgs, result.Duplicates, err = s.dropDuplicates(ctx, repo, req.DedupHours, msgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(msgs) == 0 {
+		return nil, nil, domain.ErrNoRecipients
+	}
+
+	result.Capped, err = s.applyFrequencyCap(ctx, repo, broadcast.Category, msgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var links []domain.ShortLink
+	if broadcast.ShortenLinks {
+		if links, err = s.shortenLinks(msgs); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	result.BroadcastID = broadcast.ID
+	result.Queued = len(msgs) - result.Capped
+	return msgs, links, nil
+}
+
+// expandAudience builds one message per distinct number of the requested
+// lists and segment, skipping numbers already in recipients, the normalized
+// req.Recipient. Contacts with an invalid number or missing an attribute
+// their body needs are skipped and counted.
+func (s *BroadcastService) expandAudience(ctx context.Context, broadcast domain.Broadcast, req CreateBroadcastRequest, recipients []string) ([]domain.Message, int, error) {
+	audience, err := parseAudience(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	contacts, err := s.contacts.AudienceContacts(ctx, audience, 0)
+	if err != nil {
+		return nil, 0, fmt.Errorf("expand audience: %w", err)
+	}
+
+	explicit := make(map[string]bool, len(recipients))
+	for _, to := range recipients {
+		explicit[to] = true
+	}
+
+	msgs := make([]domain.Message, 0, len(contacts))
+	skipped := 0
+	for _, c := range contacts {
+		// Stored as the frequency cap and dedup checks look the number up.
+		to, err := domain.NormalizePhone(c.Phone)
+		if err != nil {
+			skipped++
+			continue
+		}
+		if explicit[to] {
+			continue
+		}
+		explicit[to] = true
+
+		variant, tmpl := bodyFor(broadcast, req.Body, to)
+		body, err := domain.RenderTemplate(tmpl, c.TemplateVars())
+		if err != nil {
+			skipped++
+			continue
+		}
+		msg := domain.NewMessage(broadcast.ID, to, body)
+		msg.Variant = variant
+		if _, ok := domain.LoadZone(c.Attributes[domain.TimeZoneAttribute]); ok {
+			msg.TimeZone = c.Attributes[domain.TimeZoneAttribute]
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, skipped, nil
+}
+
+// normalizeRecipients returns the distinct numbers of phones in E.164 form,
+// in their original order, with the number of invalid ones left out.
+func normalizeRecipients(phones []string) ([]string, int) {
+	seen := make(map[string]bool, len(phones))
+	out := make([]string, 0, len(phones))
+	invalid := 0
+	for _, raw := range phones {
+		phone, err := domain.NormalizePhone(raw)
+		if err != nil {
+			invalid++
+			continue
+		}
+		if !seen[phone] {
+			seen[phone] = true
+			out = append(out, phone)
+		}
+	}
+	return out, invalid
+}
+
+// parseAudience returns the contacts req targets by list and segment.
+func parseAudience(req CreateBroadcastRequest) (ports.Audience, error) {
+	audience := ports.Audience{ListIDs: req.ListIDs}
+	if req.Segment != "" {
+		seg, err := domain.ParseSegment(req.Segment)
+		if err != nil {
+			return ports.Audience{}, err
+		}
+		audience.Segment = seg
+	}
+	return audience, nil
+}
+
+// bodyFor returns the A/B test variant a recipient of broadcast is assigned
+// to and that variant's body; without variants it returns no variant and body.
+func bodyFor(broadcast domain.Broadcast, body, to string) (string, string) {
+	if len(broadcast.Variants) == 0 {
+		return "", body
+	}
+	v := domain.AssignVariant(broadcast.Variants, broadcast.ID, suppressionKey(to))
+	return v.Name, v.Body
+}
+
+// resolveSender looks up a sender ID in the registry. An empty name means
+// the provider's default originator and yields nil.
+func (s *BroadcastService) resolveSender(ctx context.Context, name string) (*domain.SenderID, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if s.senders == nil {
+		return nil, fmt.Errorf("%w: %q", domain.ErrSenderNotFound, name)
+	}
+
+	sender, err := s.senders.GetSender(ctx, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrSenderNotFound) {
+			return nil, fmt.Errorf("%w: %q", domain.ErrSenderNotFound, name)
+		}
+		return nil, fmt.Errorf("get sender: %w", err)
+	}
+	return &sender, nil
+}
+
+// dropDisallowed stamps the sender on each message and removes messages to
+// countries the sender may not send to, returning the remaining messages
+// with the number removed. A nil sender allows every country.
+func dropDisallowed(sender *domain.SenderID, msgs []domain.Message) ([]domain.Message, int) {
+	if sender == nil {
+		return msgs, 0
+	}
+
+	kept := msgs[:0]
+	for _, msg := range msgs {
+		if sender.Allows(suppressionKey(msg.To)) {
+			msg.Sender = sender.Sender
+			kept = append(kept, msg)
+		}
+	}
+	return kept, len(msgs) - len(kept)
+}
+
+// dropWithoutZone removes messages the delivery window cannot be applied to,
+// because the recipient's country has no known time zone or the window never
+// opens in all of its zones, and returns the remaining messages with the
+// number removed.
+func dropWithoutZone(window domain.DeliveryWindow, msgs []domain.Message) ([]domain.Message, int) {
+	if window.IsZero() {
+		return msgs, 0
+	}
+
+	kept := msgs[:0]
+	for _, msg := range msgs {
+		if window.CheckRecipient(msg.TimeZone, msg.To) == nil {
+			kept = append(kept, msg)
+		}
+	}
+	return kept, len(msgs) - len(kept)
+}
+
+// dropSuppressed removes messages to numbers on the global suppression list
+// or that of sender, and returns the remaining messages with the number removed.
+func (s *BroadcastService) dropSuppressed(ctx context.Context, sender string, msgs []domain.Message) ([]domain.Message, int, error) {
+	if s.suppressions == nil || len(msgs) == 0 {
+		return msgs, 0, nil
+	}
+
+	phones := make([]string, len(msgs))
+	for i, msg := range msgs {
+		phones[i] = suppressionKey(msg.To)
+	}
+
+	suppressed, err := s.suppressions.SuppressedPhones(ctx, sender, phones)
+	if err != nil {
+		return nil, 0, fmt.Errorf("check suppressions: %w", err)
+	}
+	if len(suppressed) == 0 {
+		return msgs, 0, nil
+	}
+
+	kept := msgs[:0]
+	for i, msg := range msgs {
+		if !suppressed[phones[i]] {
+			kept = append(kept, msg)
+		}
+	}
+	return kept, len(msgs) - len(kept), nil
+}
+
+// outgoingStatuses are the statuses of messages that were sent or are still
+// on their way, which count towards the frequency cap and duplicate checks
+// when a broadcast is created.
+var outgoingStatuses = []domain.Status{domain.StatusPending, domain.StatusQueued, domain.StatusSent, domain.StatusDelivered}
+
+// dropDuplicates removes messages whose recipient got the same body within
+// the last dedupHours, from an earlier broadcast or earlier in msgs, and
+// returns the remaining messages with the number removed.
+func (s *BroadcastService) dropDuplicates(ctx context.Context, repo ports.MessageRepository, dedupHours int, msgs []domain.Message) ([]domain.Message, int, error) {
+	if dedupHours <= 0 || len(msgs) == 0 {
+		return msgs, 0, nil
+	}
+
+	hashes := make([]string, len(msgs))
+	for i, msg := range msgs {
+		hashes[i] = msg.ContentHash
+	}
+
+	since := time.Now().UTC().Add(-time.Duration(dedupHours) * time.Hour)
+	seen, err := repo.RecentContentHashes(ctx, hashes, since, outgoingStatuses)
+	if err != nil {
+		return nil, 0, fmt.Errorf("check duplicates: %w", err)
+	}
+
+	kept := msgs[:0]
+	for _, msg := range msgs {
+		if seen[msg.ContentHash] {
+			continue
+		}
+		seen[msg.ContentHash] = true
+		kept = append(kept, msg)
+	}
+	return kept, len(msgs) - len(kept), nil
+}
+
+// applyFrequencyCap marks the messages whose recipient already has as many
+// marketing messages in the cap window as allowed, counting earlier messages
+// of msgs too, as suppressed. The messages are still saved, so the cap is
+// visible per recipient. It returns the number of capped messages.
+func (s *BroadcastService) applyFrequencyCap(ctx context.Context, repo ports.MessageRepository, category domain.Category, msgs []domain.Message) (int, error) {
+	if !s.frequencyCap.Applies(category) || len(msgs) == 0 {
+		return 0, nil
+	}
+
+	phones := make([]string, len(msgs))
+	for i, msg := range msgs {
+		phones[i] = suppressionKey(msg.To)
+	}
+
+	since := time.Now().UTC().Add(-s.frequencyCap.Window)
+	counts, err := repo.CountMarketingMessages(ctx, phones, since, outgoingStatuses)
+	if err != nil {
+		return 0, fmt.Errorf("check frequency cap: %w", err)
+	}
+
+	capped := 0
+	for i := range msgs {
+		if counts[phones[i]] >= s.frequencyCap.Limit {
+			msgs[i].Status = domain.StatusSuppressed
+			msgs[i].ErrorCode = domain.ErrorCodeFrequencyCapped
+			msgs[i].ErrorDescription = s.frequencyCap.Reason()
+			capped++
+			continue
+		}
+		counts[phones[i]]++
+	}
+	return capped, nil
+}
+
+// shortenLinks rewrites the URLs in the bodies of the messages that will be
+// sent to short links, and returns the links to save with the messages.
+// Capped messages are never sent and keep their bodies.
+func (s *BroadcastService) shortenLinks(msgs []domain.Message) ([]domain.ShortLink, error) {
+	if s.shortLinkBase == "" {
+		return nil, errors.New("link shortening is not configured")
+	}
+
+	var links []domain.ShortLink
+	for i := range msgs {
+		if msgs[i].Status != domain.StatusPending {
+			continue
+		}
+		msgLinks, err := domain.ShortenLinks(s.shortLinkBase, &msgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("shorten links: %w", err)
+		}
+		links = append(links, msgLinks...)
+	}
+	return links, nil
+}
+
+// suppressionKey is the form a number takes on the suppression list: E.164
+// when it parses as a phone number, as given otherwise.
+func suppressionKey(to string) string {
+	if phone, err := domain.NormalizePhone(to); err == nil {
+		return phone
+	}
+	return to
+}
+
+// conversationReplyBroadcast names the daily system broadcast holding replies
+// sent from a conversation thread.
+const conversationReplyBroadcast = "Conversation replies"
+
+// ReplyInThread queues a single message to a phone number as part of its
+// conversation thread. The message is grouped under the day's system
+// broadcast and goes out through the outbox like any other.
+func (s *BroadcastService) ReplyInThread(ctx context.Context, phone, body string) (domain.Message, error) {
+	return s.QueueSystemMessage(ctx, SystemMessageRequest{Broadcast: conversationReplyBroadcast, To: phone, Body: body})
+}
+
+// singleMessageBroadcast names the daily system broadcast holding messages
+// sent through the single-message API.
+const singleMessageBroadcast = "Single messages"
+
+// SingleMessageRequest is the input for QueueSingleMessage.
+type SingleMessageRequest struct {
+	To       string
+	Sender   string // Registered sender ID; empty uses the provider's default
+	Body     string
+	Priority domain.Priority // Empty means normal
+}
+
+// QueueSingleMessage queues one transactional SMS without creating a
+// broadcast for it; it is grouped under the day's system broadcast.
+func (s *BroadcastService) QueueSingleMessage(ctx context.Context, req SingleMessageRequest) (domain.Message, error) {
+	return s.QueueSystemMessage(ctx, SystemMessageRequest{
+		Broadcast: singleMessageBroadcast,
+		To:        req.To,
+		Sender:    req.Sender,
+		Body:      req.Body,
+		Priority:  req.Priority,
+	})
+}
+
+// BroadcastReport is a broadcast with its statistics.
+type BroadcastReport struct {
+	Broadcast domain.Broadcast
+	Stats     domain.BroadcastStats
+	Variants  []VariantStats // One per A/B test variant, in the broadcast's order
+}
+
+// VariantStats are the statistics of the messages of one A/B test variant.
+type VariantStats struct {
+	Variant domain.Variant
+	Stats   domain.BroadcastStats
+}
 
-	if err := s.repo.SaveBroadcast(ctx, broadcast); err != nil {
-		return domain.Broadcast{}, fmt.Errorf("save broadcast: %w", err)
+// GetBroadcastStats returns a broadcast with its message counts by status
+// and its link clicks, overall and per A/B test variant.
+func (s *BroadcastService) GetBroadcastStats(ctx context.Context, id uuid.UUID) (BroadcastReport, error) {
+	broadcast, err := s.repo.FindBroadcast(ctx, id)
+	if err != nil {
+		return BroadcastReport{}, err
 	}
 
-	msgs := make([]domain.Message, 0, len(req.Recipient))
-	for _, to := range req.Recipient {
-		msgs = append(msgs, domain.NewMessage(broadcast.ID, to, req.Body))
+	byVariant, err := s.repo.BroadcastStats(ctx, id)
+	if err != nil {
+		return BroadcastReport{}, err
 	}
 
-	if err := s.repo.SaveMessages(ctx, msgs); err != nil {
-		return domain.Broadcast{}, fmt.Errorf("save messages: %w", err)
+	report := BroadcastReport{Broadcast: *broadcast}
+	for _, stats := range byVariant {
+		report.Stats.Merge(stats)
 	}
+	for _, v := range broadcast.Variants {
+		report.Variants = append(report.Variants, VariantStats{Variant: v, Stats: byVariant[v.Name]})
+	}
+	return report, nil
+}
+
+// GetMessage returns a message with its current status.
+func (s *BroadcastService) GetMessage(ctx context.Context, id uuid.UUID) (*domain.Message, error) {
+	return s.repo.GetMessage(ctx, id)
+}
 
-	s.log.Info("broadcast created", "broadcast_id", broadcast.ID, "recipients", len(msgs))
-	return broadcast, nil
+// priorityPublishGrace holds a high-priority message back from the outbox
+// poll while QueueSystemMessage publishes it directly, so the two never send
+// it twice. Should the direct publish not happen, the poll sends it after this.
+const priorityPublishGrace = 30 * time.Second
+
+// SystemMessageRequest is the input for QueueSystemMessage.
+type SystemMessageRequest struct {
+	Broadcast string // Name of the daily system broadcast the message is grouped under
+	To        string
+	Sender    string // Registered sender ID; empty uses the provider's default
+	Body      string
+	Priority  domain.Priority // Empty means normal
+}
+
+// QueueSystemMessage saves a single transactional message to the outbox
+// under the day's system broadcast. Where a publisher is configured, a
+// high-priority message is published to the priority lane straight away;
+// should that fail, the outbox publisher picks it up a little later.
+func (s *BroadcastService) QueueSystemMessage(ctx context.Context, req SystemMessageRequest) (domain.Message, error) {
+	phone, err := domain.NormalizePhone(req.To)
+	if err != nil {
+		return domain.Message{}, err
+	}
+
+	sender, err := s.resolveSender(ctx, req.Sender)
+	if err != nil {
+		return domain.Message{}, err
+	}
+	if sender != nil && !sender.Allows(phone) {
+		return domain.Message{}, fmt.Errorf("%w: %s", domain.ErrSenderNotAllowed, phone)
+	}
+
+	if s.suppressions != nil {
+		suppressed, err := s.suppressions.SuppressedPhones(ctx, req.Sender, []string{phone})
+		if err != nil {
+			return domain.Message{}, fmt.Errorf("check suppressions: %w", err)
+		}
+		if suppressed[phone] {
+			return domain.Message{}, domain.ErrRecipientSuppressed
+		}
+	}
+
+	broadcast := domain.SystemBroadcast(req.Broadcast, time.Now())
+	msg := domain.NewMessage(broadcast.ID, phone, req.Body)
+	msg.Sender = req.Sender
+	direct := req.Priority == domain.PriorityHigh && s.publisher != nil
+	if req.Priority != "" {
+		msg.Priority = req.Priority
+	}
+	if direct {
+		msg.NotBefore = msg.CreatedAt.Add(priorityPublishGrace)
+	}
+
+	err = s.repo.WithinTx(ctx, func(repo ports.MessageRepository) error {
+		if err := repo.EnsureBroadcast(ctx, broadcast); err != nil {
+			return err
+		}
+		if err := repo.SaveMessages(ctx, []domain.Message{msg}); err != nil {
+			return fmt.Errorf("save message: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Message{}, err
+	}
+
+	s.log.Info("system message queued", "msg_id", msg.ID, "to", phone, "broadcast", req.Broadcast, "priority", msg.Priority)
+
+	if direct && s.publish(ctx, msg) {
+		msg.Status = domain.StatusQueued
+	}
+	return msg, nil
+}
+
+// PurgeExpiredIdempotencyKeys deletes idempotency keys past their window.
+func (s *BroadcastService) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	n, err := s.repo.PurgeExpiredIdempotencyKeys(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("purge idempotency keys: %w", err)
+	}
+	if n > 0 {
+		s.log.Info("purged expired idempotency keys", "count", n)
+	}
+	return n, nil
+}
+
+// fingerprint hashes the fields that define a broadcast request, so a key
+// reused with a different body can be told apart from a genuine retry.
+func fingerprint(req CreateBroadcastRequest) string {
+	canonical, _ := json.Marshal(struct {
+		Name         string           `json:"name"`
+		Body         string           `json:"body"`
+		Recipients   []string         `json:"recipients"`
+		CallbackURL  string           `json:"callback_url"`
+		ListIDs      []uuid.UUID      `json:"list_ids"`
+		Segment      string           `json:"segment"`
+		Window       string           `json:"window"`
+		Category     domain.Category  `json:"category"`
+		DedupHours   int              `json:"dedup_hours"`
+		Sender       string           `json:"sender"`
+		ShortenLinks bool             `json:"shorten_links"`
+		Variants     []domain.Variant `json:"variants"`
+	}{req.Name, req.Body, req.Recipient, req.CallbackURL, req.ListIDs, req.Segment, req.Window.String(), req.Category, req.DedupHours, req.Sender, req.ShortenLinks, req.Variants})
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
 }
 
 // PublishPendingMessages reads pending outbox messages and publishes them to the queue.
+// Messages outside their broadcast's delivery window are deferred instead.
 // This is called by the outbox-publisher binary on a poll interval.
 func (s *BroadcastService) PublishPendingMessages(ctx context.Context, batchSize int) (int, error) {
 	msgs, err := s.repo.GetPendingMessages(ctx, batchSize)
@@ -69,54 +748,209 @@ func (s *BroadcastService) PublishPendingMessages(ctx context.Context, batchSize
 		return 0, fmt.Errorf("get pending messages: %w", err)
 	}
 
+	msgs, err = s.deferOutsideWindow(ctx, msgs, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
 	published := 0
 	for _, msg := range msgs {
-		if err := s.repo.UpdateMessageStatus(ctx, msg.ID, domain.StatusQueued); err != nil {
-			s.log.Error("mark queued failed", "msg_id", msg.ID, "err", err)
+		if s.publish(ctx, msg) {
+			published++
+		}
+	}
+
+	return published, nil
+}
+
+// publish marks a pending message queued and hands it to the message queue,
+// reporting whether it was published.
+func (s *BroadcastService) publish(ctx context.Context, msg domain.Message) bool {
+	if err := s.repo.UpdateMessageStatus(ctx, msg.ID, domain.StatusQueued); err != nil {
+		s.log.Error("mark queued failed", "msg_id", msg.ID, "err", err)
+		return false
+	}
+
+	if err := s.publisher.Publish(ctx, msg); err != nil {
+		// Roll back to pending so the next poll retries it.
+		_ = s.repo.UpdateMessageStatus(ctx, msg.ID, domain.StatusPending)
+		s.log.Error("publish failed", "msg_id", msg.ID, "err", err)
+		return false
+	}
+
+	s.log.Info("message queued", "msg_id", msg.ID, "to", msg.To)
+	return true
+}
+
+// deferOutsideWindow postpones messages that fall outside their broadcast's
+// delivery window, in the recipient's local time, until the window next
+// opens. Without a timezone attribute, a recipient in a country spanning
+// several zones gets the message only while the window is open in all of
+// them. It returns the messages that may be published now.
+func (s *BroadcastService) deferOutsideWindow(ctx context.Context, msgs []domain.Message, now time.Time) ([]domain.Message, error) {
+	if len(msgs) == 0 {
+		return msgs, nil
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var broadcastIDs []uuid.UUID
+	for _, msg := range msgs {
+		if !seen[msg.BroadcastID] {
+			seen[msg.BroadcastID] = true
+			broadcastIDs = append(broadcastIDs, msg.BroadcastID)
+		}
+	}
+
+	windows, err := s.repo.DeliveryWindows(ctx, broadcastIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get delivery windows: %w", err)
+	}
+	if len(windows) == 0 {
+		return msgs, nil
+	}
+
+	due := msgs[:0]
+	deferred := make(map[time.Time][]uuid.UUID)
+	for _, msg := range msgs {
+		window, ok := windows[msg.BroadcastID]
+		if !ok {
+			due = append(due, msg)
 			continue
 		}
 
-		if err := s.publisher.Publish(ctx, msg); err != nil {
-			// Roll back to pending so the next poll retries it.
-			_ = s.repo.UpdateMessageStatus(ctx, msg.ID, domain.StatusPending)
-			s.log.Error("publish failed", "msg_id", msg.ID, "err", err)
+		locs, err := domain.RecipientLocations(msg.TimeZone, msg.To)
+		if err != nil {
+			// Creation rejects such recipients; this is an older message.
+			if err := s.suppress(ctx, msg, domain.ErrorCodeNoTimeZone, err.Error()); err != nil {
+				s.log.Error("suppress message failed", "msg_id", msg.ID, "err", err)
+			}
+			continue
+		}
+		if window.AllowsIn(now, locs) {
+			due = append(due, msg)
 			continue
 		}
+		until, ok := window.NextOpenIn(now, locs)
+		if !ok {
+			reason := fmt.Sprintf("window %s never opens in every time zone of the recipient's country", window)
+			if err := s.suppress(ctx, msg, domain.ErrorCodeNoTimeZone, reason); err != nil {
+				s.log.Error("suppress message failed", "msg_id", msg.ID, "err", err)
+			}
+			continue
+		}
+		until = until.UTC()
+		deferred[until] = append(deferred[until], msg.ID)
+	}
 
-		published++
-		s.log.Info("message queued", "msg_id", msg.ID, "to", msg.To)
+	for until, ids := range deferred {
+		if err := s.repo.DeferMessages(ctx, ids, until); err != nil {
+			return nil, err
+		}
+		s.log.Info("messages deferred to delivery window", "count", len(ids), "until", until)
 	}
 
-	return published, nil
+	return due, nil
 }
 
 // SendMessage calls the SMS provider for a single queued message.
 // This is called by the sender-worker binary for each message it dequeues.
 func (s *BroadcastService) SendMessage(ctx context.Context, msg domain.Message) error {
-	result, err := s.provider.Send(ctx, msg)
+	// The number may have opted out after the message was created. Earlier
+	// entries were applied at creation; a message created later on purpose,
+	// such as the reply confirming a STOP, must still go out.
+	if s.suppressions != nil {
+		suppressed, err := s.suppressions.SuppressedSince(ctx, msg.Sender, suppressionKey(msg.To), msg.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("check suppressions: %w", err)
+		}
+		if suppressed {
+			return s.suppress(ctx, msg, domain.ErrorCodeOptedOut, "recipient is on the suppression list")
+		}
+	}
+
+	capped, err := s.frequencyCapped(ctx, msg)
 	if err != nil {
-		_ = s.repo.UpdateMessageStatus(ctx, msg.ID, domain.StatusFailed)
-		return fmt.Errorf("provider send: %w", err)
+		return err
+	}
+	if capped {
+		return s.suppress(ctx, msg, domain.ErrorCodeFrequencyCapped, s.frequencyCap.Reason())
 	}
 
-	if err := s.repo.SetProviderID(ctx, msg.ID, result.ProviderID); err != nil {
-		s.log.Error("set provider id failed", "msg_id", msg.ID, "err", err)
+	result, err := s.provider.Send(ctx, msg)
+	if err != nil {
+		if err := s.repo.UpdateMessageStatus(ctx, msg.ID, domain.StatusFailed); err == nil {
+			s.enqueueCallbacks(ctx, msg)
+		}
+		return fmt.Errorf("provider send: %w", err)
 	}
 
+	// Mark sent before attaching the provider ID: receipts are matched by
+	// provider and provider ID, so a fast DLR can never be overwritten by the sent transition.
 	if err := s.repo.UpdateMessageStatus(ctx, msg.ID, domain.StatusSent); err != nil {
 		return fmt.Errorf("update status sent: %w", err)
 	}
+	s.enqueueCallbacks(ctx, msg)
+
+	ref := domain.ProviderRef{Provider: result.Provider, ProviderID: result.ProviderID}
+	if err := s.repo.SetProviderID(ctx, msg.ID, ref); err != nil {
+		s.log.Error("set provider id failed", "msg_id", msg.ID, "err", err)
+	} else if n, err := s.repo.ReplayPendingReceipts(ctx, []domain.ProviderRef{ref}); err != nil {
+		s.log.Error("replay pending dlr failed", "msg_id", msg.ID, "err", err)
+	} else if n > 0 {
+		s.log.Info("replayed early DLR", "msg_id", msg.ID, "provider_id", result.ProviderID)
+		s.enqueueCallbacks(ctx, msg)
+	}
 
 	s.log.Info("message sent", "msg_id", msg.ID, "provider_id", result.ProviderID)
 	return nil
 }
 
-// HandleDLR processes a delivery receipt from the SMS provider webhook.
-func (s *BroadcastService) HandleDLR(ctx context.Context, dlr ports.DLRPayload) error {
-	if err := s.repo.UpdateMessageStatusByProviderID(ctx, dlr.ProviderID.String(), dlr.Status); err != nil {
-		return fmt.Errorf("update dlr status: %w", err)
+// frequencyCapped reports whether a marketing message would exceed the
+// frequency cap. Only messages already handed to the provider count here;
+// the creation-time check also counted messages that were still pending.
+func (s *BroadcastService) frequencyCapped(ctx context.Context, msg domain.Message) (bool, error) {
+	if s.frequencyCap.Limit <= 0 {
+		return false, nil
 	}
 
-	s.log.Info("DLR received", "provider_id", dlr.ProviderID, "status", dlr.Status)
+	category, err := s.repo.BroadcastCategory(ctx, msg.BroadcastID)
+	if err != nil {
+		return false, fmt.Errorf("check frequency cap: %w", err)
+	}
+	if !s.frequencyCap.Applies(category) {
+		return false, nil
+	}
+
+	since := time.Now().UTC().Add(-s.frequencyCap.Window)
+	phone := suppressionKey(msg.To)
+	counts, err := s.repo.CountMarketingMessages(ctx, []string{phone}, since, []domain.Status{domain.StatusSent, domain.StatusDelivered})
+	if err != nil {
+		return false, fmt.Errorf("check frequency cap: %w", err)
+	}
+	return counts[phone] >= s.frequencyCap.Limit, nil
+}
+
+// suppress marks a message suppressed instead of sending it.
+func (s *BroadcastService) suppress(ctx context.Context, msg domain.Message, errorCode, reason string) error {
+	suppressed, err := s.repo.SuppressMessage(ctx, msg.ID, errorCode, reason)
+	if err != nil {
+		return fmt.Errorf("update status suppressed: %w", err)
+	}
+	if !suppressed {
+		// A redelivered message another worker already handled; its
+		// callback went out then.
+		s.log.Info("message already handled", "msg_id", msg.ID)
+		return nil
+	}
+	s.enqueueCallbacks(ctx, msg)
+	s.log.Info("message suppressed", "msg_id", msg.ID, "to", msg.To, "reason", errorCode)
 	return nil
 }
+
+// enqueueCallbacks queues a status callback for the message's current status.
+// Failures are logged only: callbacks must never fail the send itself.
+func (s *BroadcastService) enqueueCallbacks(ctx context.Context, msg domain.Message) {
+	if err := s.repo.EnqueueStatusCallbacks(ctx, []uuid.UUID{msg.ID}); err != nil {
+		s.log.Error("enqueue status callback failed", "msg_id", msg.ID, "err", err)
+	}
+}
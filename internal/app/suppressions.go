@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+)
+
+// SuppressionService manages the numbers that must not be messaged.
+type SuppressionService struct {
+	repo ports.SuppressionRepository
+	log  *slog.Logger
+}
+
+// NewSuppressionService wires the service with its dependencies.
+func NewSuppressionService(repo ports.SuppressionRepository, log *slog.Logger) *SuppressionService {
+	return &SuppressionService{repo: repo, log: log}
+}
+
+// AddSuppressions puts numbers on the global list (sender empty) or the list
+// of one sender, and returns how many were not already there.
+func (s *SuppressionService) AddSuppressions(ctx context.Context, phones []string, sender, reason, source string) (int64, error) {
+	entries := make([]domain.Suppression, 0, len(phones))
+	now := time.Now().UTC()
+	for _, raw := range phones {
+		phone, err := domain.NormalizePhone(raw)
+		if err != nil {
+			return 0, err
+		}
+		entries = append(entries, domain.Suppression{Phone: phone, Sender: sender, Reason: reason, Source: source, CreatedAt: now})
+	}
+
+	added, err := s.repo.AddSuppressions(ctx, entries)
+	if err != nil {
+		return 0, fmt.Errorf("add suppressions: %w", err)
+	}
+
+	s.log.Info("numbers suppressed", "added", added, "sender", sender, "source", source)
+	return added, nil
+}
+
+// RemoveSuppression takes a number off the global list or one sender's list.
+func (s *SuppressionService) RemoveSuppression(ctx context.Context, phone, sender string) error {
+	phone, err := domain.NormalizePhone(phone)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.RemoveSuppression(ctx, phone, sender); err != nil {
+		return err
+	}
+
+	s.log.Info("suppression removed", "phone", phone, "sender", sender)
+	return nil
+}
+
+// ListSuppressions returns a page of suppression entries.
+func (s *SuppressionService) ListSuppressions(ctx context.Context, filter ports.SuppressionFilter) ([]domain.Suppression, error) {
+	if filter.Phone != "" {
+		phone, err := domain.NormalizePhone(filter.Phone)
+		if err != nil {
+			return nil, err
+		}
+		filter.Phone = phone
+	}
+	return s.repo.ListSuppressions(ctx, filter)
+}
+
+// ImportSuppressionsRequest is the input for a bulk suppression import.
+type ImportSuppressionsRequest struct {
+	CSV         io.Reader
+	PhoneColumn string // Defaults to "phone"; other columns are ignored
+	Sender      string // Empty for the global list
+	Reason      string
+}
+
+// ImportSuppressionsResult describes the outcome of ImportSuppressions.
+type ImportSuppressionsResult struct {
+	Added        int64 // Numbers that were not on the list yet
+	Rejected     int
+	RejectedRows []RejectedRow
+}
+
+// ImportSuppressions streams a CSV of numbers onto the list in chunks.
+func (s *SuppressionService) ImportSuppressions(ctx context.Context, req ImportSuppressionsRequest) (ImportSuppressionsResult, error) {
+	reader := csv.NewReader(req.CSV)
+	reader.ReuseRecord = true
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return ImportSuppressionsResult{}, fmt.Errorf("%w: file is empty", domain.ErrInvalidUpload)
+	}
+	if err != nil {
+		return ImportSuppressionsResult{}, fmt.Errorf("%w: read header: %v", domain.ErrInvalidUpload, err)
+	}
+
+	phoneColumn := req.PhoneColumn
+	if phoneColumn == "" {
+		phoneColumn = "phone"
+	}
+	phoneIdx, ok := indexColumns(header)[strings.ToLower(phoneColumn)]
+	if !ok {
+		return ImportSuppressionsResult{}, fmt.Errorf("%w: no %q column", domain.ErrInvalidUpload, phoneColumn)
+	}
+
+	var result ImportSuppressionsResult
+	reject := func(line int, err error) {
+		result.Rejected++
+		if len(result.RejectedRows) < maxReportedRejections {
+			result.RejectedRows = append(result.RejectedRows, RejectedRow{Line: line, Error: err.Error()})
+		}
+	}
+
+	now := time.Now().UTC()
+	chunk := make([]domain.Suppression, 0, importChunkSize)
+	flush := func() error {
+		added, err := s.repo.AddSuppressions(ctx, chunk)
+		if err != nil {
+			return fmt.Errorf("add suppressions: %w", err)
+		}
+		result.Added += added
+		chunk = chunk[:0]
+		return nil
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return result, fmt.Errorf("read csv: %w", err)
+			}
+			reject(parseErr.StartLine, parseErr.Err)
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		if phoneIdx >= len(record) {
+			reject(line, fmt.Errorf("no %q value", phoneColumn))
+			continue
+		}
+		phone, err := domain.NormalizePhone(record[phoneIdx])
+		if err != nil {
+			reject(line, err)
+			continue
+		}
+
+		chunk = append(chunk, domain.Suppression{
+			Phone:     phone,
+			Sender:    req.Sender,
+			Reason:    req.Reason,
+			Source:    domain.SuppressionSourceImport,
+			CreatedAt: now,
+		})
+		if len(chunk) == importChunkSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if len(chunk) > 0 {
+		if err := flush(); err != nil {
+			return result, err
+		}
+	}
+
+	s.log.Info("suppressions imported", "added", result.Added, "rejected", result.Rejected, "sender", req.Sender)
+	return result, nil
+}
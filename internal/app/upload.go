@@ -0,0 +1,273 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// uploadChunkSize is how many messages are buffered before they are
+	// written, which bounds memory use regardless of file size.
+	uploadChunkSize = 10000
+
+	// maxReportedRejections caps the rejected rows returned to the client;
+	// the total count is always reported.
+	maxReportedRejections = 1000
+)
+
+// UploadBroadcastRequest is the input for creating a broadcast from a CSV
+// file. Body and variant bodies may contain {{column}} placeholders filled
+// from each row, and a "timezone" column sets the recipient's zone for the
+// delivery window. Rows outside the sender's countries are rejected.
+type UploadBroadcastRequest struct {
+	domain.BroadcastOptions
+
+	PhoneColumn string // Header of the phone number column; defaults to "phone"
+	CSV         io.Reader
+
+	// BroadcastID, if set, is used instead of a new ID. Jobs pass
+	// domain.JobBroadcastID so that running a job twice cannot create two
+	// broadcasts.
+	BroadcastID uuid.UUID
+
+	// Progress, if set, is called with the running totals after each chunk.
+	Progress func(progress UploadBroadcastResult)
+}
+
+// RejectedRow is a CSV row that was skipped during import.
+type RejectedRow struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// UploadBroadcastResult describes the outcome of CreateBroadcastFromCSV.
+type UploadBroadcastResult struct {
+	CreateBroadcastResult
+	Rejected     int
+	RejectedRows []RejectedRow // At most maxReportedRejections entries
+}
+
+// CreateBroadcastFromCSV streams a CSV recipient list into a new broadcast.
+// Rows are validated one by one and written in chunks inside one transaction;
+// invalid rows are skipped and reported with their line numbers.
+func (s *BroadcastService) CreateBroadcastFromCSV(ctx context.Context, req UploadBroadcastRequest) (UploadBroadcastResult, error) {
+	reader := csv.NewReader(req.CSV)
+	reader.ReuseRecord = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return UploadBroadcastResult{}, fmt.Errorf("%w: file is empty", domain.ErrInvalidUpload)
+	}
+	if err != nil {
+		return UploadBroadcastResult{}, fmt.Errorf("%w: read header: %v", domain.ErrInvalidUpload, err)
+	}
+	columns := indexColumns(header)
+
+	phoneColumn := req.PhoneColumn
+	if phoneColumn == "" {
+		phoneColumn = "phone"
+	}
+	phoneIdx, ok := columns[strings.ToLower(phoneColumn)]
+	if !ok {
+		return UploadBroadcastResult{}, fmt.Errorf("%w: no %q column", domain.ErrInvalidUpload, phoneColumn)
+	}
+
+	variables := templateVariables(req.Body, req.Variants)
+	for _, name := range variables {
+		if _, ok := columns[strings.ToLower(name)]; !ok {
+			return UploadBroadcastResult{}, fmt.Errorf("%w: body uses {{%s}} but the file has no %q column", domain.ErrInvalidUpload, name, name)
+		}
+	}
+
+	sender, err := s.resolveSender(ctx, req.Sender)
+	if err != nil {
+		return UploadBroadcastResult{}, err
+	}
+
+	broadcast := newBroadcast(req.BroadcastOptions, req.BroadcastID)
+	zoneIdx, hasZone := columns[domain.TimeZoneAttribute]
+
+	result := UploadBroadcastResult{CreateBroadcastResult: CreateBroadcastResult{BroadcastID: broadcast.ID}}
+	reject := func(line int, err error) {
+		result.Rejected++
+		if len(result.RejectedRows) < maxReportedRejections {
+			result.RejectedRows = append(result.RejectedRows, RejectedRow{Line: line, Error: err.Error()})
+		}
+	}
+
+	err = s.repo.WithinTx(ctx, func(repo ports.MessageRepository) error {
+		if err := repo.SaveBroadcast(ctx, broadcast); err != nil {
+			return fmt.Errorf("save broadcast: %w", err)
+		}
+
+		chunk := make([]domain.Message, 0, uploadChunkSize)
+		flush := func() error {
+			kept, suppressed, err := s.dropSuppressed(ctx, broadcast.Sender, chunk)
+			if err != nil {
+				return err
+			}
+			// Earlier chunks are visible to both checks through the transaction.
+			kept, duplicates, err := s.dropDuplicates(ctx, repo, req.DedupHours, kept)
+			if err != nil {
+				return err
+			}
+			capped, err := s.applyFrequencyCap(ctx, repo, broadcast.Category, kept)
+			if err != nil {
+				return err
+			}
+			var links []domain.ShortLink
+			if broadcast.ShortenLinks {
+				if links, err = s.shortenLinks(kept); err != nil {
+					return err
+				}
+			}
+			if err := repo.SaveMessages(ctx, kept); err != nil {
+				return fmt.Errorf("save messages: %w", err)
+			}
+			if err := repo.SaveShortLinks(ctx, links); err != nil {
+				return fmt.Errorf("save short links: %w", err)
+			}
+			result.Queued += len(kept) - capped
+			result.Suppressed += suppressed
+			result.Capped += capped
+			result.Duplicates += duplicates
+			chunk = chunk[:0]
+			if req.Progress != nil {
+				req.Progress(result)
+			}
+			return nil
+		}
+
+		vars := make(map[string]string, len(variables))
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				var parseErr *csv.ParseError
+				if !errors.As(err, &parseErr) {
+					return fmt.Errorf("read csv: %w", err)
+				}
+				reject(parseErr.StartLine, parseErr.Err)
+				continue
+			}
+			line, _ := reader.FieldPos(0)
+
+			to, err := domain.NormalizePhone(record[phoneIdx])
+			if err != nil {
+				reject(line, err)
+				continue
+			}
+			if sender != nil && !sender.Allows(to) {
+				reject(line, fmt.Errorf("%w: %s", domain.ErrSenderNotAllowed, to))
+				continue
+			}
+
+			for _, name := range variables {
+				vars[name] = strings.TrimSpace(record[columns[strings.ToLower(name)]])
+			}
+			variant, tmpl := bodyFor(broadcast, req.Body, to)
+			body, err := domain.RenderTemplate(tmpl, vars)
+			if err != nil {
+				reject(line, err)
+				continue
+			}
+
+			msg := domain.NewMessage(broadcast.ID, to, body)
+			msg.Sender = broadcast.Sender
+			msg.Variant = variant
+			if hasZone {
+				if zone := strings.TrimSpace(record[zoneIdx]); zone != "" {
+					if _, ok := domain.LoadZone(zone); !ok {
+						reject(line, fmt.Errorf("unknown time zone %q", zone))
+						continue
+					}
+					msg.TimeZone = zone
+				}
+			}
+			if err := broadcast.Window.CheckRecipient(msg.TimeZone, to); err != nil {
+				reject(line, err)
+				continue
+			}
+			chunk = append(chunk, msg)
+			if len(chunk) == uploadChunkSize {
+				if err := flush(); err != nil {
+					return err
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(chunk) > 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		if result.Queued+result.Capped == 0 {
+			// Roll back the empty broadcast; the rejections explain why.
+			return domain.ErrNoRecipients
+		}
+
+		broadcast.Queued, broadcast.Rejected, broadcast.Suppressed = result.Queued, result.Rejected, result.Suppressed
+		broadcast.Capped, broadcast.Duplicates = result.Capped, result.Duplicates
+		if err := repo.SaveBroadcastCounts(ctx, broadcast); err != nil {
+			return fmt.Errorf("save broadcast counts: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNoRecipients) {
+			return result, err
+		}
+		return UploadBroadcastResult{}, err
+	}
+
+	s.log.Info("broadcast uploaded", "broadcast_id", broadcast.ID, "recipients", result.Queued, "rejected", result.Rejected, "suppressed", result.Suppressed, "capped", result.Capped, "duplicates", result.Duplicates)
+	return result, nil
+}
+
+// templateVariables returns the placeholders used by the body, or by any
+// variant's body when there are variants.
+func templateVariables(body string, variants []domain.Variant) []string {
+	if len(variants) == 0 {
+		return domain.TemplateVariables(body)
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, v := range variants {
+		for _, name := range domain.TemplateVariables(v.Body) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// indexColumns maps lower-cased, trimmed header names to their position.
+func indexColumns(header []string) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // Excel's UTF-8 byte order mark
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return columns
+}
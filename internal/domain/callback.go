@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Callback event types sent to a broadcast's callback URL.
+const (
+	EventMessageSent        = "message.sent"
+	EventMessageDelivered   = "message.delivered"
+	EventMessageFailed      = "message.failed"
+	EventMessageSuppressed  = "message.suppressed"
+	EventBroadcastCompleted = "broadcast.completed"
+)
+
+// CallbackStatus is the delivery state of a callback event.
+type CallbackStatus string
+
+const (
+	CallbackPending   CallbackStatus = "pending"   // Waiting for (re)delivery
+	CallbackSending   CallbackStatus = "sending"   // Claimed by a dispatcher
+	CallbackDelivered CallbackStatus = "delivered" // Client answered 2xx
+	CallbackFailed    CallbackStatus = "failed"    // Gave up after max attempts
+)
+
+// CallbackEvent is a status event queued for POSTing to a client application.
+type CallbackEvent struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	BroadcastID   uuid.UUID      `gorm:"type:uuid;not null;index:idx_callback_events_broadcast"`
+	MessageID     *uuid.UUID     `gorm:"type:uuid"`
+	Event         string         `gorm:"type:text;not null"`
+	URL           string         `gorm:"type:text;not null"`
+	Payload       string         `gorm:"type:jsonb;not null"`
+	DedupKey      string         `gorm:"type:text;not null;uniqueIndex:idx_callback_events_dedup"`
+	Status        CallbackStatus `gorm:"type:text;not null;default:'pending';index:idx_callback_events_due"`
+	Attempts      int            `gorm:"not null;default:0"`
+	NextAttemptAt time.Time      `gorm:"not null;index:idx_callback_events_due"`
+	LastError     string         `gorm:"type:text"`
+	CreatedAt     time.Time      `gorm:"not null"`
+	UpdatedAt     time.Time      `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (CallbackEvent) TableName() string {
+	return "callback_events"
+}
+
+// CallbackAttempt is one entry in the callback delivery log.
+type CallbackAttempt struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	EventID     uuid.UUID `gorm:"type:uuid;not null;index:idx_callback_attempts_event"`
+	Attempt     int       `gorm:"not null"`
+	StatusCode  int
+	Error       string    `gorm:"type:text"`
+	DurationMS  int64     `gorm:"not null"`
+	AttemptedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (CallbackAttempt) TableName() string {
+	return "callback_attempts"
+}
+
+// ErrCallbackNotFound is returned when a callback event does not exist.
+var ErrCallbackNotFound = errors.New("callback event not found")
+
+// ErrPrivateAddress is returned when a callback would be sent to an address
+// inside a private network.
+var ErrPrivateAddress = errors.New("callback address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// net.IP.IsPrivate does not cover.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// PublicIP reports whether ip is a publicly routable unicast address.
+// Callbacks are only sent to public addresses, so a callback URL cannot be
+// used to reach services inside our own network.
+func PublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Contact is a reusable recipient with free-form attributes that broadcast
+// bodies can reference as {{attribute}} placeholders.
+type Contact struct {
+	ID         uuid.UUID         `gorm:"type:uuid;primaryKey"`
+	Phone      string            `gorm:"type:text;not null;uniqueIndex:idx_contacts_phone"`
+	Attributes map[string]string `gorm:"type:jsonb;serializer:json;not null;default:'{}'"`
+	CreatedAt  time.Time         `gorm:"not null"`
+	UpdatedAt  time.Time         `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (Contact) TableName() string {
+	return "contacts"
+}
+
+// BeforeCreate hook ensures UUID is set before creating
+func (c *Contact) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	if c.Attributes == nil {
+		c.Attributes = map[string]string{}
+	}
+	return nil
+}
+
+// TemplateVars returns the values available to a broadcast body for this
+// contact: its attributes plus the built-in {{phone}}.
+func (c Contact) TemplateVars() map[string]string {
+	vars := make(map[string]string, len(c.Attributes)+1)
+	for k, v := range c.Attributes {
+		vars[k] = v
+	}
+	vars["phone"] = c.Phone
+	return vars
+}
+
+// ContactList is a named group of contacts a broadcast can target.
+type ContactList struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name        string    `gorm:"type:text;not null"`
+	Description string    `gorm:"type:text;not null;default:''"`
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+
+	// Members is the number of contacts in the list; read-only.
+	Members int64 `gorm:"->;-:migration"`
+}
+
+// TableName specifies the table name for GORM
+func (ContactList) TableName() string {
+	return "contact_lists"
+}
+
+// BeforeCreate hook ensures UUID is set before creating
+func (l *ContactList) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// ContactListMember links a contact to a list.
+type ContactListMember struct {
+	ListID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ContactID uuid.UUID `gorm:"type:uuid;primaryKey;index:idx_contact_list_members_contact"`
+	CreatedAt time.Time `gorm:"not null"`
+
+	List    ContactList `gorm:"foreignKey:ListID;constraint:OnDelete:CASCADE"`
+	Contact Contact     `gorm:"foreignKey:ContactID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for GORM
+func (ContactListMember) TableName() string {
+	return "contact_list_members"
+}
+
+// Contact errors
+var (
+	ErrContactNotFound = errors.New("contact not found")
+	ErrContactExists   = errors.New("contact with this phone already exists")
+	ErrListNotFound    = errors.New("contact list not found")
+)
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Direction tells messages we sent from messages we received.
+type Direction string
+
+const (
+	DirectionOutbound Direction = "outbound"
+	DirectionInbound  Direction = "inbound"
+)
+
+// ConversationEntry is one message of the thread with a phone number, either
+// an outbound Message or an InboundMessage.
+type ConversationEntry struct {
+	ID          uuid.UUID
+	Direction   Direction
+	Body        string
+	Status      Status     // Outbound only
+	BroadcastID *uuid.UUID // Outbound only
+	InReplyTo   *uuid.UUID // Inbound only: the outbound message it answers
+	Keyword     string     // Inbound only: the matched keyword, if any
+	At          time.Time  // Creation time for outbound, receipt time for inbound
+}
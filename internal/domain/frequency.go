@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Category classifies a broadcast for frequency capping.
+type Category string
+
+const (
+	CategoryMarketing     Category = "marketing"     // Counts towards and is limited by the frequency cap
+	CategoryTransactional Category = "transactional" // OTPs, receipts, replies; never capped
+)
+
+// ErrInvalidCategory is returned for an unknown broadcast category.
+var ErrInvalidCategory = errors.New("invalid category")
+
+// ParseCategory converts a string into a Category; empty means marketing.
+func ParseCategory(s string) (Category, error) {
+	switch c := Category(s); c {
+	case "":
+		return CategoryMarketing, nil
+	case CategoryMarketing, CategoryTransactional:
+		return c, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidCategory, s)
+}
+
+// ErrorCodeFrequencyCapped marks a message suppressed because its recipient
+// already received the maximum number of marketing messages.
+const ErrorCodeFrequencyCapped = "FREQUENCY_CAPPED"
+
+// FrequencyCap limits how many marketing messages one number receives within
+// a sliding window, e.g. 3 per 7 days. A zero Limit disables the cap.
+type FrequencyCap struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Applies reports whether the cap limits broadcasts of the given category.
+func (f FrequencyCap) Applies(category Category) bool {
+	return f.Limit > 0 && f.Window > 0 && category != CategoryTransactional
+}
+
+// Reason is the error description stored on capped messages.
+func (f FrequencyCap) Reason() string {
+	return fmt.Sprintf("recipient reached the limit of %d marketing messages per %s", f.Limit, f.Window)
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey remembers the outcome of a broadcast creation so a retried
+// request with the same Idempotency-Key header is answered without resending.
+type IdempotencyKey struct {
+	Key         string    `gorm:"type:text;primaryKey"`
+	Fingerprint string    `gorm:"type:text;not null"`
+	BroadcastID uuid.UUID `gorm:"type:uuid;not null"`
+	Queued      int       `gorm:"not null"`
+	Rejected    int       `gorm:"not null;default:0"`
+	Suppressed  int       `gorm:"not null;default:0"`
+	Capped      int       `gorm:"not null;default:0"`
+	Duplicates  int       `gorm:"not null;default:0"`
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index:idx_idempotency_keys_expires_at"`
+}
+
+// TableName specifies the table name for GORM
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// ErrIdempotencyConflict is returned when an idempotency key is reused with a different request.
+var ErrIdempotencyConflict = errors.New("idempotency key reused with a different request")
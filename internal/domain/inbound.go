@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// InboundMessage is a mobile-originated (MO) SMS received from a provider.
+type InboundMessage struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primaryKey"`
+	Provider    string        `gorm:"type:text;not null;uniqueIndex:idx_inbound_messages_provider_id,where:provider_id <> ''"`
+	ProviderID  string        `gorm:"type:text;not null;default:'';uniqueIndex:idx_inbound_messages_provider_id,where:provider_id <> ''"`
+	From        string        `gorm:"column:from_number;type:text;not null;index:idx_inbound_messages_from"`
+	To          string        `gorm:"column:to_number;type:text;not null"`
+	Body        string        `gorm:"type:text;not null"`
+	Keyword     string        `gorm:"type:text"` // Matched keyword, empty when none matched
+	Action      KeywordAction `gorm:"type:text"`
+	ReceivedAt  time.Time     `gorm:"not null"`
+	MessageID   *uuid.UUID    `gorm:"type:uuid;index:idx_inbound_messages_message"` // Outbound message this replies to; nil when none was sent
+	ProcessedAt *time.Time    // Set once keyword actions and replies are done
+	CreatedAt   time.Time     `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (InboundMessage) TableName() string {
+	return "inbound_messages"
+}
+
+// ErrNotReceipt is returned by a receipt parser for a provider callback that
+// carries an inbound message rather than a delivery receipt.
+var ErrNotReceipt = errors.New("not a delivery receipt")
+
+// SuppressionSourceKeyword marks numbers suppressed by an inbound STOP keyword.
+const SuppressionSourceKeyword = "keyword"
+
+// KeywordAction is what an inbound keyword does.
+type KeywordAction string
+
+const (
+	KeywordOptOut KeywordAction = "opt_out" // Add the sender to the suppression list
+	KeywordOptIn  KeywordAction = "opt_in"  // Remove the sender from the suppression list
+	KeywordHelp   KeywordAction = "help"    // Only send the reply
+)
+
+// KeywordRule maps inbound keywords in one language to an action and an
+// automatic reply.
+type KeywordRule struct {
+	Language string        `json:"language"`
+	Action   KeywordAction `json:"action"`
+	Keywords []string      `json:"keywords"`
+	Reply    string        `json:"reply"` // Empty sends no reply
+}
+
+// DefaultKeywordRules are used when no rules file is configured.
+func DefaultKeywordRules() []KeywordRule {
+	return []KeywordRule{
+		{
+			Language: "en",
+			Action:   KeywordOptOut,
+			Keywords: []string{"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT"},
+			Reply:    "You have been unsubscribed and will receive no further messages. Reply START to resubscribe.",
+		},
+		{
+			Language: "en",
+			Action:   KeywordOptIn,
+			Keywords: []string{"START", "UNSTOP", "SUBSCRIBE"},
+			Reply:    "You have been resubscribed. Reply STOP to unsubscribe.",
+		},
+		{
+			Language: "en",
+			Action:   KeywordHelp,
+			Keywords: []string{"HELP", "INFO"},
+			Reply:    "Reply STOP to unsubscribe or START to resubscribe.",
+		},
+		{
+			Language: "th",
+			Action:   KeywordOptOut,
+			Keywords: []string{"หยุด", "ยกเลิก"},
+			Reply:    "คุณได้ยกเลิกการรับข้อความแล้ว ส่ง เริ่ม เพื่อรับข้อความอีกครั้ง",
+		},
+		{
+			Language: "th",
+			Action:   KeywordOptIn,
+			Keywords: []string{"เริ่ม"},
+			Reply:    "คุณได้สมัครรับข้อความอีกครั้งแล้ว ส่ง หยุด เพื่อยกเลิก",
+		},
+		{
+			Language: "th",
+			Action:   KeywordHelp,
+			Keywords: []string{"ช่วยเหลือ"},
+			Reply:    "ส่ง หยุด เพื่อยกเลิก หรือ เริ่ม เพื่อรับข้อความอีกครั้ง",
+		},
+	}
+}
+
+// ParseKeywordRules decodes a JSON array of rules and validates it.
+func ParseKeywordRules(data []byte) ([]KeywordRule, error) {
+	var rules []KeywordRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("decode keyword rules: %w", err)
+	}
+
+	for i, rule := range rules {
+		switch rule.Action {
+		case KeywordOptOut, KeywordOptIn, KeywordHelp:
+		default:
+			return nil, fmt.Errorf("keyword rule %d: unknown action %q", i, rule.Action)
+		}
+		if len(rule.Keywords) == 0 {
+			return nil, fmt.Errorf("keyword rule %d: no keywords", i)
+		}
+	}
+	return rules, nil
+}
+
+// MatchKeyword returns the rule whose keyword the body consists of, and the
+// keyword itself. Help keywords also match as the first word of a longer
+// body; opt-outs and opt-ins do not, so "Stop by our store" or "Start time?"
+// leave the subscription alone. Matching ignores case and surrounding
+// punctuation. The first matching rule wins.
+func MatchKeyword(rules []KeywordRule, body string) (*KeywordRule, string) {
+	text := strings.ToUpper(strings.TrimFunc(body, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}))
+	if text == "" {
+		return nil, ""
+	}
+	first := strings.TrimFunc(strings.Fields(text)[0], unicode.IsPunct)
+
+	for i := range rules {
+		for _, keyword := range rules[i].Keywords {
+			keyword = strings.ToUpper(strings.TrimSpace(keyword))
+			if keyword != "" && (text == keyword || (rules[i].Action == KeywordHelp && first == keyword)) {
+				return &rules[i], keyword
+			}
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus is the lifecycle state of a background broadcast creation job.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"    // Waiting for a job worker
+	JobRunning   JobStatus = "running"   // Recipients are being expanded into the outbox
+	JobCompleted JobStatus = "completed" // Broadcast created
+	JobFailed    JobStatus = "failed"    // Nothing was created; see Error
+)
+
+// BroadcastJob creates a broadcast in the background so large recipient
+// lists do not have to be inserted within an HTTP request.
+type BroadcastJob struct {
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Status JobStatus `gorm:"type:text;not null;default:'queued';index:idx_broadcast_jobs_status"`
+
+	BroadcastOptions `gorm:"embedded"`
+
+	PhoneColumn string      `gorm:"type:text;not null;default:''"`
+	ListIDs     []uuid.UUID `gorm:"type:jsonb;serializer:json"` // Contact lists expanded when the job runs
+	Segment     string      `gorm:"type:text;not null;default:''"`
+	TotalRows   int         `gorm:"not null;default:0"`
+	Queued      int         `gorm:"not null;default:0"`
+	Rejected    int         `gorm:"not null;default:0"`
+	Suppressed  int         `gorm:"not null;default:0"`               // Rows on the suppression list
+	Capped      int         `gorm:"not null;default:0"`               // Rows saved as suppressed by the frequency cap
+	Duplicates  int         `gorm:"not null;default:0"`               // Rows skipped by the dedup window
+	Errors      string      `gorm:"type:jsonb;not null;default:'[]'"` // Rejected rows with line numbers
+	Error       string      `gorm:"type:text"`
+	BroadcastID *uuid.UUID  `gorm:"type:uuid"`
+	CreatedAt   time.Time   `gorm:"not null"`
+	UpdatedAt   time.Time   `gorm:"not null"`
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
+
+// TableName specifies the table name for GORM
+func (BroadcastJob) TableName() string {
+	return "broadcast_jobs"
+}
+
+// jobBroadcastNamespace seeds the IDs of broadcasts created by jobs.
+var jobBroadcastNamespace = uuid.MustParse("e96dd398-0fe4-4747-aa6c-d0efe9c3f082")
+
+// JobBroadcastID returns the ID of the broadcast a job creates. It is derived
+// from the job, so when a job runs again (its worker died, or a slow run was
+// claimed as stale) the second run conflicts with the broadcast the first
+// committed instead of creating another one.
+func JobBroadcastID(jobID uuid.UUID) uuid.UUID {
+	return uuid.NewSHA1(jobBroadcastNamespace, jobID[:])
+}
+
+// JobChunkSize is the size of the pieces a job's input is stored in.
+const JobChunkSize = 1 << 20
+
+// BroadcastJobChunk is one piece of a job's input, the recipient list as CSV
+// with a header row. Storing it in pieces keeps uploads and imports from
+// holding the whole file in memory.
+type BroadcastJobChunk struct {
+	JobID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Seq   int       `gorm:"primaryKey;autoIncrement:false"`
+	Data  []byte    `gorm:"type:bytea;not null"`
+}
+
+// TableName specifies the table name for GORM
+func (BroadcastJobChunk) TableName() string {
+	return "broadcast_job_chunks"
+}
+
+// ErrJobNotFound is returned when a broadcast job does not exist.
+var ErrJobNotFound = errors.New("job not found")
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrLinkNotFound is returned for an unknown short link code.
+var ErrLinkNotFound = errors.New("link not found")
+
+const (
+	// shortCodeLength gives 62^10 (about 8e17) codes, so random codes do not
+	// collide in practice even across hundreds of millions of links.
+	shortCodeLength = 10
+	shortCodeChars  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var (
+	bodyURL   = regexp.MustCompile(`https?://[^\s<>"]+`)
+	shortCode = regexp.MustCompile(`^[0-9A-Za-z]{10}$`)
+)
+
+// ShortLink is a short URL standing in for a URL in one message's body. Each
+// recipient gets their own code, so a click is attributed to a message.
+type ShortLink struct {
+	Code        string    `gorm:"type:text;primaryKey"`
+	MessageID   uuid.UUID `gorm:"type:uuid;not null;index:idx_short_links_message"`
+	BroadcastID uuid.UUID `gorm:"type:uuid;not null"`
+	URL         string    `gorm:"type:text;not null"` // The original URL the code redirects to
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (ShortLink) TableName() string {
+	return "short_links"
+}
+
+// LinkClick is one visit to a short link.
+type LinkClick struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	Code        string    `gorm:"type:text;not null"`
+	MessageID   uuid.UUID `gorm:"type:uuid;not null"`
+	BroadcastID uuid.UUID `gorm:"type:uuid;not null;index:idx_link_clicks_broadcast"`
+	UserAgent   string    `gorm:"type:text;not null;default:''"`
+	ClickedAt   time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (LinkClick) TableName() string {
+	return "link_clicks"
+}
+
+// ValidShortCode reports whether code has the form of a generated code.
+func ValidShortCode(code string) bool {
+	return shortCode.MatchString(code)
+}
+
+// NewShortCode returns a random short link code.
+func NewShortCode() (string, error) {
+	code := make([]byte, shortCodeLength)
+	max := big.NewInt(int64(len(shortCodeChars)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = shortCodeChars[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// ShortenLinks replaces the http(s) URLs in msg.Body with short links under
+// baseURL and returns the links to store. A URL is only replaced when the
+// short link is shorter, and one that repeats within the body shares a code.
+// ContentHash keeps describing the original body.
+func ShortenLinks(baseURL string, msg *Message) ([]ShortLink, error) {
+	prefix := strings.TrimSuffix(baseURL, "/") + "/"
+
+	var links []ShortLink
+	var err error
+	codes := make(map[string]string)
+	msg.Body = bodyURL.ReplaceAllStringFunc(msg.Body, func(match string) string {
+		// Punctuation ending a sentence is not part of the URL.
+		target := strings.TrimRight(match, ".,;:!?)]}'")
+		trailing := match[len(target):]
+		if err != nil || strings.HasPrefix(target, prefix) || len(prefix)+shortCodeLength >= len(target) {
+			return match
+		}
+
+		code, ok := codes[target]
+		if !ok {
+			if code, err = NewShortCode(); err != nil {
+				return match
+			}
+			codes[target] = code
+			links = append(links, ShortLink{
+				Code:        code,
+				MessageID:   msg.ID,
+				BroadcastID: msg.BroadcastID,
+				URL:         target,
+				CreatedAt:   msg.CreatedAt,
+			})
+		}
+		return prefix + code + trailing
+	})
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
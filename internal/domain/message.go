@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,23 +13,82 @@ import (
 type Status string
 
 const (
-	StatusPending   Status = "pending"   // Saved to outbox, not yet queued
-	StatusQueued    Status = "queued"    // Published to message queue
-	StatusSent      Status = "sent"      // Accepted by SMS provider
-	StatusDelivered Status = "delivered" // Confirmed delivered to recipient (DLR)
-	StatusFailed    Status = "failed"    // Permanently failed
+	StatusPending    Status = "pending"    // Saved to outbox, not yet queued
+	StatusQueued     Status = "queued"     // Published to message queue
+	StatusSent       Status = "sent"       // Accepted by SMS provider
+	StatusDelivered  Status = "delivered"  // Confirmed delivered to recipient (DLR)
+	StatusFailed     Status = "failed"     // Permanently failed
+	StatusSuppressed Status = "suppressed" // Not sent: opted out or over the frequency cap; see ErrorCode
 )
 
+// IsValid reports whether s is one of the known lifecycle states.
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusQueued, StatusSent, StatusDelivered, StatusFailed, StatusSuppressed:
+		return true
+	}
+	return false
+}
+
+// IsFinal reports whether s is a terminal state that a later receipt must not overwrite.
+func (s Status) IsFinal() bool {
+	return s == StatusDelivered || s == StatusFailed || s == StatusSuppressed
+}
+
+// Priority selects the queue lane a message is sent on.
+type Priority string
+
+const (
+	PriorityNormal Priority = "normal" // Broadcasts; published by the outbox poll
+	PriorityHigh   Priority = "high"   // Time-critical messages such as one-time passwords
+)
+
+// ErrInvalidPriority is returned for an unknown message priority.
+var ErrInvalidPriority = errors.New("invalid priority")
+
+// ParsePriority converts a string into a Priority; empty means normal.
+func ParsePriority(s string) (Priority, error) {
+	switch p := Priority(s); p {
+	case "":
+		return PriorityNormal, nil
+	case PriorityNormal, PriorityHigh:
+		return p, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidPriority, s)
+}
+
 // Message is the core domain entity representing a single SMS.
 type Message struct {
 	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
 	BroadcastID uuid.UUID `gorm:"type:uuid;not null;index:idx_messages_broadcast"`
-	To          string    `gorm:"column:to_number;type:text;not null"`
+	To          string    `gorm:"column:to_number;type:text;not null;index:idx_messages_to_created"`
+	Sender      string    `gorm:"type:text;not null;default:''"` // Originator; empty uses the provider's default
 	Body        string    `gorm:"type:text;not null"`
-	Status      Status    `gorm:"type:text;not null;default:'pending';index:idx_messages_status_created"`
+	Priority    Priority  `gorm:"type:text;not null;default:'normal'"`
+	Variant     string    `gorm:"type:text;not null;default:''"` // A/B test variant the body came from; empty without variants
+	Status      Status    `gorm:"type:text;not null;default:'pending';index:idx_messages_status_created;index:idx_messages_status_not_before"`
+	Provider    string    `gorm:"type:text;not null;default:''"` // Name the provider's receipts arrive under, set with ProviderID
 	ProviderID  string    `gorm:"type:text;index:idx_messages_provider_id,where:provider_id IS NOT NULL"`
-	CreatedAt   time.Time `gorm:"not null;index:idx_messages_status_created"`
+	CreatedAt   time.Time `gorm:"not null;index:idx_messages_status_created;index:idx_messages_to_created;index:idx_messages_content_hash,priority:2"`
 	UpdatedAt   time.Time `gorm:"not null"`
+
+	// TimeZone is the recipient's IANA time zone when known from a contact
+	// attribute or CSV column; empty infers it from the country code.
+	TimeZone string `gorm:"type:text;not null;default:''"`
+
+	// ContentHash is ContentHash(To, Body), matched by later broadcasts that
+	// skip recipients who recently received the same text.
+	ContentHash string `gorm:"type:text;not null;default:'';index:idx_messages_content_hash,priority:1"`
+
+	// NotBefore is the earliest time the outbox publisher may publish the
+	// message. It starts at CreatedAt and moves forward when the message
+	// falls outside its broadcast's delivery window.
+	NotBefore time.Time `gorm:"not null;default:now();index:idx_messages_status_not_before"`
+
+	// Delivery receipt details, populated from the provider's DLR.
+	ErrorCode        string     `gorm:"type:text"`
+	ErrorDescription string     `gorm:"type:text"`
+	DeliveredAt      *time.Time `gorm:"type:timestamptz"`
 }
 
 // TableName specifies the table name for GORM
@@ -47,6 +107,12 @@ func (m *Message) BeforeCreate(tx *gorm.DB) error {
 	if m.UpdatedAt.IsZero() {
 		m.UpdatedAt = time.Now().UTC()
 	}
+	if m.NotBefore.IsZero() {
+		m.NotBefore = m.CreatedAt
+	}
+	if m.Priority == "" {
+		m.Priority = PriorityNormal
+	}
 	return nil
 }
 
@@ -58,8 +124,31 @@ func (m *Message) BeforeUpdate(tx *gorm.DB) error {
 
 // Broadcast groups a collection of messages sent together.
 type Broadcast struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
-	Name      string    `gorm:"type:text;not null"`
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name        string    `gorm:"type:text;not null"`
+	CallbackURL string    `gorm:"type:text;not null;default:''"` // Receives status events; empty disables callbacks
+	Category    Category  `gorm:"type:text;not null;default:'marketing'"`
+	Sender      string    `gorm:"type:text;not null;default:''"` // Registered sender ID; empty uses the provider's default
+
+	// ShortenLinks rewrites URLs in message bodies to per-recipient short
+	// links whose clicks are recorded.
+	ShortenLinks bool `gorm:"not null;default:false"`
+
+	// Variants split the recipients between several bodies for an A/B
+	// test; empty when every recipient gets the same body.
+	Variants []Variant `gorm:"type:jsonb;serializer:json"`
+
+	// Window limits publishing to a time of day in the recipient's zone.
+	Window DeliveryWindow `gorm:"embedded;embeddedPrefix:window_"`
+
+	// Counts of the creation, saved with the messages so a job that finds
+	// its broadcast already committed reports the same totals.
+	Queued     int `gorm:"not null;default:0"`
+	Rejected   int `gorm:"not null;default:0"`
+	Suppressed int `gorm:"not null;default:0"`
+	Capped     int `gorm:"not null;default:0"`
+	Duplicates int `gorm:"not null;default:0"`
+
 	CreatedAt time.Time `gorm:"not null"`
 	Messages  []Message `gorm:"foreignKey:BroadcastID;constraint:OnDelete:CASCADE"`
 }
@@ -80,15 +169,100 @@ func (b *Broadcast) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// ProviderRef identifies a message at its provider. Provider IDs are only
+// unique within one provider, so receipts are matched on both.
+type ProviderRef struct {
+	Provider   string
+	ProviderID string
+}
+
+// Receipt is the outcome reported by the provider for a single message.
+type Receipt struct {
+	Provider         string
+	ProviderID       string
+	Status           Status
+	ErrorCode        string
+	ErrorDescription string
+	DeliveredAt      *time.Time // Set only when Status is StatusDelivered
+}
+
+// Ref returns the provider reference the receipt is for.
+func (r Receipt) Ref() ProviderRef {
+	return ProviderRef{Provider: r.Provider, ProviderID: r.ProviderID}
+}
+
+// PendingDLR is a receipt that arrived before its provider ID was stored on a
+// message. It is replayed once SetProviderID attaches the ID, or purged after ExpiresAt.
+type PendingDLR struct {
+	ID               int64      `gorm:"primaryKey;autoIncrement"`
+	Provider         string     `gorm:"type:text;not null"`
+	ProviderID       string     `gorm:"type:text;not null;index:idx_pending_dlrs_provider_id"`
+	Status           Status     `gorm:"type:text;not null"`
+	ErrorCode        string     `gorm:"type:text"`
+	ErrorDescription string     `gorm:"type:text"`
+	DeliveredAt      *time.Time `gorm:"type:timestamptz"`
+	ReceivedAt       time.Time  `gorm:"not null"`
+	ExpiresAt        time.Time  `gorm:"not null;index:idx_pending_dlrs_expires_at"`
+}
+
+// TableName specifies the table name for GORM
+func (PendingDLR) TableName() string {
+	return "pending_dlrs"
+}
+
+// BroadcastOptions are the settings of a broadcast to create, shared by JSON
+// requests, CSV uploads and the jobs that create either in the background.
+type BroadcastOptions struct {
+	Name        string   `gorm:"type:text;not null"`
+	Body        string   `gorm:"type:text;not null"`                     // May contain {{placeholders}}
+	CallbackURL string   `gorm:"type:text;not null;default:''"`          // Receives status events; empty disables callbacks
+	Category    Category `gorm:"type:text;not null;default:'marketing'"` // Empty means marketing
+	Sender      string   `gorm:"type:text;not null;default:''"`          // Registered sender ID; empty uses the provider's default
+
+	// Window restricts publishing to a time of day in each recipient's
+	// local time; the zero value allows any time.
+	Window DeliveryWindow `gorm:"embedded;embeddedPrefix:window_"`
+
+	// DedupHours skips recipients who were sent the same body, by any
+	// broadcast, within this many hours; 0 disables the check.
+	DedupHours int `gorm:"not null;default:0"`
+
+	// ShortenLinks replaces URLs in bodies with per-recipient short links
+	// whose clicks are recorded against the message.
+	ShortenLinks bool `gorm:"not null;default:false"`
+
+	// Variants, when set, replace Body for an A/B test: each number gets the
+	// body of one variant, picked deterministically in the variants' split.
+	Variants []Variant `gorm:"type:jsonb;serializer:json"`
+}
+
 // NewBroadcast creates a new Broadcast with a generated ID.
 func NewBroadcast(name string) Broadcast {
 	return Broadcast{
 		ID:        uuid.New(),
 		Name:      name,
+		Category:  CategoryMarketing,
 		CreatedAt: time.Now().UTC(),
 	}
 }
 
+// systemBroadcastNamespace seeds the IDs of system broadcasts.
+var systemBroadcastNamespace = uuid.MustParse("0b6f4a8e-3c1d-4f7a-9e2b-5d8c7a1f6e30")
+
+// SystemBroadcast returns the broadcast that groups messages the system sends
+// on its own, such as keyword auto-replies, one per name and UTC day. Its ID
+// is derived from both, so every writer arrives at the same broadcast. System
+// broadcasts are transactional and never frequency capped.
+func SystemBroadcast(name string, at time.Time) Broadcast {
+	day := at.UTC().Format("2006-01-02")
+	return Broadcast{
+		ID:        uuid.NewSHA1(systemBroadcastNamespace, []byte(name+"/"+day)),
+		Name:      name + " " + day,
+		Category:  CategoryTransactional,
+		CreatedAt: at.UTC(),
+	}
+}
+
 // NewMessage creates a new pending Message for a given broadcast.
 func NewMessage(broadcastID uuid.UUID, to, body string) Message {
 	now := time.Now().UTC()
@@ -97,9 +271,12 @@ func NewMessage(broadcastID uuid.UUID, to, body string) Message {
 		BroadcastID: broadcastID,
 		To:          to,
 		Body:        body,
+		Priority:    PriorityNormal,
 		Status:      StatusPending,
+		ContentHash: ContentHash(to, body),
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		NotBefore:   now,
 	}
 }
 
@@ -108,4 +285,7 @@ var (
 	ErrMessageNotFound   = errors.New("message not found")
 	ErrBroadcastNotFound = errors.New("broadcast not found")
 	ErrInvalidStatus     = errors.New("invalid status transition")
+	ErrUnknownStatus     = errors.New("unknown status")
+	ErrNoRecipients      = errors.New("no valid recipients")
+	ErrInvalidUpload     = errors.New("invalid recipient file")
 )
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OTPStatus is the lifecycle state of a one-time password.
+type OTPStatus string
+
+const (
+	OTPPending  OTPStatus = "pending"  // Sent, waiting for verification
+	OTPVerified OTPStatus = "verified" // Verified; cannot be used again
+	OTPLocked   OTPStatus = "locked"   // Too many wrong codes
+	OTPReplaced OTPStatus = "replaced" // A newer code was sent to the same number
+)
+
+// OTP errors
+var (
+	ErrOTPNotFound    = errors.New("otp not found")
+	ErrOTPExpired     = errors.New("otp expired")
+	ErrOTPUsed        = errors.New("otp already verified")
+	ErrOTPMismatch    = errors.New("incorrect code")
+	ErrOTPLocked      = errors.New("too many incorrect codes; try again later")
+	ErrOTPTooSoon     = errors.New("a code was sent to this number moments ago")
+	ErrInvalidOTPBody = errors.New("otp body must contain {{code}}")
+)
+
+// OTPCodePlaceholder is the template variable the code is rendered into.
+const OTPCodePlaceholder = "code"
+
+// OTP is a one-time password sent to a phone number. Only a keyed hash of
+// the code is stored.
+type OTP struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Phone       string     `gorm:"type:text;not null;index:idx_otps_phone_created"`
+	CodeHash    string     `gorm:"type:text;not null"`
+	MessageID   *uuid.UUID `gorm:"type:uuid"` // The SMS that carried the code
+	Status      OTPStatus  `gorm:"type:text;not null;default:'pending'"`
+	Attempts    int        `gorm:"not null;default:0"` // Verification attempts so far
+	MaxAttempts int        `gorm:"not null"`
+	ExpiresAt   time.Time  `gorm:"not null"`
+	VerifiedAt  *time.Time `gorm:"type:timestamptz"`
+	CreatedAt   time.Time  `gorm:"not null;index:idx_otps_phone_created"`
+}
+
+// TableName specifies the table name for GORM
+func (OTP) TableName() string {
+	return "otps"
+}
+
+// GenerateOTPCode returns a random numeric code of the given length.
+func GenerateOTPCode(length int) (string, error) {
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+	return string(code), nil
+}
+
+// HashOTPCode returns the keyed hash stored for code. The OTP ID is mixed in
+// so equal codes never share a hash.
+func HashOTPCode(secret []byte, id uuid.UUID, code string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(id[:])
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckOTPCode reports in constant time whether code matches the OTP.
+func (o OTP) CheckOTPCode(secret []byte, code string) bool {
+	return hmac.Equal([]byte(o.CodeHash), []byte(HashOTPCode(secret, o.ID, code)))
+}
+
+// OTPPolicy bounds the lifetime, guessing and resending of one-time passwords.
+type OTPPolicy struct {
+	CodeLength  int
+	TTL         time.Duration
+	MaxAttempts int // Wrong codes before one OTP is locked
+
+	// LockoutAttempts wrong codes for one number, across all its OTPs within
+	// LockoutWindow, lock the number out of sending and verifying.
+	LockoutAttempts int
+	LockoutWindow   time.Duration
+
+	// ResendInterval is the least time between two codes sent to one number.
+	ResendInterval time.Duration
+}
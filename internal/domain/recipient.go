@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	e164        = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	phoneFiller = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+	placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+)
+
+// Recipient errors
+var (
+	ErrInvalidPhone    = errors.New("invalid phone number")
+	ErrMissingVariable = errors.New("missing template variable")
+)
+
+// NormalizePhone strips common formatting from a phone number and checks
+// that the result is in E.164 form. A leading international "00" becomes "+".
+func NormalizePhone(raw string) (string, error) {
+	phone := phoneFiller.Replace(strings.TrimSpace(raw))
+	if strings.HasPrefix(phone, "00") {
+		phone = "+" + phone[2:]
+	}
+	if !e164.MatchString(phone) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPhone, raw)
+	}
+	return phone, nil
+}
+
+// TemplateVariables returns the distinct {{name}} placeholders used in body, sorted.
+func TemplateVariables(body string) []string {
+	seen := make(map[string]bool)
+	for _, m := range placeholder.FindAllStringSubmatch(body, -1) {
+		seen[m[1]] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RenderTemplate replaces every {{name}} placeholder in body with vars[name].
+// A placeholder without a non-empty value is an error, so no recipient gets
+// a message with a blank where their name should be.
+func RenderTemplate(body string, vars map[string]string) (string, error) {
+	var missing string
+	out := placeholder.ReplaceAllStringFunc(body, func(m string) string {
+		name := placeholder.FindStringSubmatch(m)[1]
+		v := vars[name]
+		if v == "" && missing == "" {
+			missing = name
+		}
+		return v
+	})
+
+	if missing != "" {
+		return "", fmt.Errorf("%w: %s", ErrMissingVariable, missing)
+	}
+	return out, nil
+}
+
+// ContentHash identifies a message by recipient and body for duplicate
+// detection across broadcasts. The number is normalized to E.164 when it
+// parses, and the body is compared case-insensitively with runs of
+// whitespace collapsed.
+func ContentHash(to, body string) string {
+	if phone, err := NormalizePhone(to); err == nil {
+		to = phone
+	}
+	normalized := strings.ToLower(strings.Join(strings.Fields(body), " "))
+
+	sum := sha256.Sum256([]byte(to + "\x00" + normalized))
+	return hex.EncodeToString(sum[:])
+}
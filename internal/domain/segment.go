@@ -0,0 +1,348 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidSegment is returned when a segment expression cannot be parsed.
+var ErrInvalidSegment = errors.New("invalid segment")
+
+// maxSegmentLength bounds the expressions accepted from clients.
+const maxSegmentLength = 4096
+
+// Segment is a parsed filter over contact attributes, e.g.
+//
+//	city = "Bangkok" AND tier IN ("gold", "platinum")
+//
+// It is one of SegmentAnd, SegmentOr, SegmentNot or SegmentCondition.
+// Adapters translate it into their own query language.
+type Segment interface {
+	segment()
+}
+
+// SegmentAnd matches contacts matching both sides.
+type SegmentAnd struct{ Left, Right Segment }
+
+// SegmentOr matches contacts matching either side.
+type SegmentOr struct{ Left, Right Segment }
+
+// SegmentNot matches contacts not matching Inner.
+type SegmentNot struct{ Inner Segment }
+
+// SegmentCondition compares one attribute with one or more values.
+// Field "phone" refers to the contact's phone number.
+type SegmentCondition struct {
+	Field  string
+	Op     string // =, !=, <, <=, >, >=, IN, NOT IN
+	Values []SegmentValue
+}
+
+// SegmentValue is a string or number literal.
+type SegmentValue struct {
+	Text     string
+	Number   float64
+	IsNumber bool
+}
+
+func (SegmentAnd) segment()       {}
+func (SegmentOr) segment()        {}
+func (SegmentNot) segment()       {}
+func (SegmentCondition) segment() {}
+
+// ParseSegment parses a filter expression. The grammar is
+//
+//	expr      = and { "OR" and }
+//	and       = unary { "AND" unary }
+//	unary     = "NOT" unary | "(" expr ")" | condition
+//	condition = field ( op value | [ "NOT" ] "IN" "(" value { "," value } ")" )
+//	op        = "=" | "!=" | "<>" | "<" | "<=" | ">" | ">="
+//	value     = "double" | 'single' quoted string | number
+//
+// Keywords are case-insensitive; field names are attribute keys.
+func ParseSegment(expr string) (Segment, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidSegment)
+	}
+	if len(expr) > maxSegmentLength {
+		return nil, fmt.Errorf("%w: expression longer than %d characters", ErrInvalidSegment, maxSegmentLength)
+	}
+
+	tokens, err := lexSegment(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &segmentParser{tokens: tokens}
+	seg, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	return seg, nil
+}
+
+// ── Lexer ─────────────────────────────────────────────────────────────────────
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based column, for error messages
+}
+
+func lexSegment(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", start})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", start})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokComma, ",", start})
+			i++
+
+		case r == '"' || r == '\'':
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("%w: unterminated string at column %d", ErrInvalidSegment, start)
+			}
+			i++ // closing quote
+			tokens = append(tokens, token{tokString, sb.String(), start})
+
+		case strings.ContainsRune("=!<>", r):
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '<' && runes[i+1] == '>')) {
+				op += string(runes[i+1])
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("%w: unexpected \"!\" at column %d", ErrInvalidSegment, start)
+			}
+			i += len(op)
+			if op == "<>" {
+				op = "!="
+			}
+			tokens = append(tokens, token{tokOp, op, start})
+
+		case r == '-' || unicode.IsDigit(r):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[i:j]), start})
+			i = j
+
+		case r == '_' || unicode.IsLetter(r):
+			j := i + 1
+			for j < len(runes) && (runes[j] == '_' || unicode.IsLetter(runes[j]) || unicode.IsMark(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, token{tokIdent, string(runes[i:j]), start})
+			i = j
+
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at column %d", ErrInvalidSegment, r, start)
+		}
+	}
+
+	return append(tokens, token{tokEOF, "end of expression", len(runes) + 1}), nil
+}
+
+// ── Parser ────────────────────────────────────────────────────────────────────
+
+type segmentParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *segmentParser) peek() token { return p.tokens[p.pos] }
+
+func (p *segmentParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is the given keyword and consumes it.
+func (p *segmentParser) keyword(word string) bool {
+	if tok := p.peek(); tok.kind == tokIdent && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *segmentParser) errorf(tok token, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at column %d", ErrInvalidSegment, fmt.Sprintf(format, args...), tok.pos)
+}
+
+func (p *segmentParser) parseOr() (Segment, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = SegmentOr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *segmentParser) parseAnd() (Segment, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = SegmentAnd{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *segmentParser) parseUnary() (Segment, error) {
+	if p.keyword("NOT") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return SegmentNot{Inner: inner}, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			return nil, p.errorf(tok, "expected \")\" but found %q", tok.text)
+		}
+		return inner, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *segmentParser) parseCondition() (Segment, error) {
+	field := p.next()
+	if field.kind != tokIdent || isSegmentKeyword(field.text) {
+		return nil, p.errorf(field, "expected attribute name but found %q", field.text)
+	}
+
+	if p.keyword("NOT") {
+		if !p.keyword("IN") {
+			return nil, p.errorf(p.peek(), "expected IN after NOT")
+		}
+		return p.parseIn(field.text, "NOT IN")
+	}
+	if p.keyword("IN") {
+		return p.parseIn(field.text, "IN")
+	}
+
+	op := p.next()
+	if op.kind != tokOp {
+		return nil, p.errorf(op, "expected operator after %q but found %q", field.text, op.text)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if op.text != "=" && op.text != "!=" && !value.IsNumber {
+		return nil, p.errorf(op, "%s needs a number", op.text)
+	}
+
+	return SegmentCondition{Field: field.text, Op: op.text, Values: []SegmentValue{value}}, nil
+}
+
+func (p *segmentParser) parseIn(field, op string) (Segment, error) {
+	if tok := p.next(); tok.kind != tokLParen {
+		return nil, p.errorf(tok, "expected \"(\" after %s", op)
+	}
+
+	var values []SegmentValue
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		tok := p.next()
+		if tok.kind == tokRParen {
+			break
+		}
+		if tok.kind != tokComma {
+			return nil, p.errorf(tok, "expected \",\" or \")\" but found %q", tok.text)
+		}
+	}
+
+	return SegmentCondition{Field: field, Op: op, Values: values}, nil
+}
+
+func (p *segmentParser) parseValue() (SegmentValue, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokString:
+		return SegmentValue{Text: tok.text}, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return SegmentValue{}, p.errorf(tok, "invalid number %q", tok.text)
+		}
+		return SegmentValue{Text: tok.text, Number: n, IsNumber: true}, nil
+	}
+	return SegmentValue{}, p.errorf(tok, "expected a quoted string or number but found %q", tok.text)
+}
+
+func isSegmentKeyword(s string) bool {
+	switch strings.ToUpper(s) {
+	case "AND", "OR", "NOT", "IN":
+		return true
+	}
+	return false
+}
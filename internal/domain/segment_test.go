@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func cond(field, op string, values ...SegmentValue) SegmentCondition {
+	return SegmentCondition{Field: field, Op: op, Values: values}
+}
+
+func text(s string) SegmentValue { return SegmentValue{Text: s} }
+
+func number(s string, n float64) SegmentValue {
+	return SegmentValue{Text: s, Number: n, IsNumber: true}
+}
+
+func TestParseSegment(t *testing.T) {
+	a := cond("a", "=", text("1"))
+	b := cond("b", "=", text("2"))
+	c := cond("c", "=", text("3"))
+
+	tests := []struct {
+		name string
+		expr string
+		want Segment
+	}{
+		{"equals", `city = "Bangkok"`, cond("city", "=", text("Bangkok"))},
+		{"not equals", `city != "Bangkok"`, cond("city", "!=", text("Bangkok"))},
+		{"angle not equals", `city <> "Bangkok"`, cond("city", "!=", text("Bangkok"))},
+		{"number comparison", `age >= 18`, cond("age", ">=", number("18", 18))},
+		{"negative decimal", `balance < -2.5`, cond("balance", "<", number("-2.5", -2.5))},
+		{"in", `tier IN ("gold", 'platinum')`, cond("tier", "IN", text("gold"), text("platinum"))},
+		{"not in", `tier NOT IN ("bronze")`, cond("tier", "NOT IN", text("bronze"))},
+		{"keywords are case insensitive", `tier not in ("bronze") and a = "1"`, SegmentAnd{cond("tier", "NOT IN", text("bronze")), a}},
+
+		{"single quotes", `name = 'O"Brien'`, cond("name", "=", text(`O"Brien`))},
+		{"escaped quote", `name = 'O\'Brien'`, cond("name", "=", text("O'Brien"))},
+		{"escaped backslash", `path = "a\\b"`, cond("path", "=", text(`a\b`))},
+		{"keyword inside string", `note = "x AND y"`, cond("note", "=", text("x AND y"))},
+		{"unicode field and value", `เมือง = "กรุงเทพ"`, cond("เมือง", "=", text("กรุงเทพ"))},
+
+		{"AND binds tighter than OR", `a = "1" OR b = "2" AND c = "3"`, SegmentOr{a, SegmentAnd{b, c}}},
+		{"AND binds tighter than OR on the left", `a = "1" AND b = "2" OR c = "3"`, SegmentOr{SegmentAnd{a, b}, c}},
+		{"parentheses override precedence", `(a = "1" OR b = "2") AND c = "3"`, SegmentAnd{SegmentOr{a, b}, c}},
+		{"AND is left associative", `a = "1" AND b = "2" AND c = "3"`, SegmentAnd{SegmentAnd{a, b}, c}},
+		{"NOT binds tighter than AND", `NOT a = "1" AND b = "2"`, SegmentAnd{SegmentNot{a}, b}},
+		{"NOT of a group", `NOT (a = "1" OR b = "2")`, SegmentNot{SegmentOr{a, b}}},
+		{"double NOT", `NOT NOT a = "1"`, SegmentNot{SegmentNot{a}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSegment(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segment = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSegmentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantMsg string
+	}{
+		{"empty", "   ", "empty expression"},
+		{"too long", `a = "` + strings.Repeat("x", maxSegmentLength) + `"`, "longer than"},
+		{"unterminated string", `city = "Bangkok`, "unterminated string at column 8"},
+		{"bare bang", `city ! "x"`, `unexpected "!" at column 6`},
+		{"unexpected character", `city = "x" & a = "1"`, "column 12"},
+		{"missing operator", `city "Bangkok"`, "expected operator"},
+		{"missing value", `city =`, "expected a quoted string or number"},
+		{"unquoted value", `city = Bangkok`, "expected a quoted string or number"},
+		{"text with ordering operator", `age > "18"`, "> needs a number"},
+		{"invalid number", `age = 1.2.3`, "invalid number"},
+		{"keyword as field", `AND = "1"`, "expected attribute name"},
+		{"NOT without IN", `tier NOT ("gold")`, "expected IN after NOT"},
+		{"IN without parenthesis", `tier IN "gold"`, `expected "(" after IN`},
+		{"empty IN list", `tier IN ()`, "expected a quoted string or number"},
+		{"unclosed IN list", `tier IN ("gold" "silver")`, `expected "," or ")"`},
+		{"unclosed group", `(a = "1"`, `expected ")"`},
+		{"trailing token", `a = "1" b = "2"`, `unexpected "b" at column 9`},
+		{"dangling OR", `a = "1" OR`, "expected attribute name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSegment(tt.expr)
+			if !errors.Is(err, ErrInvalidSegment) {
+				t.Fatalf("error = %v, want %v", err, ErrInvalidSegment)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SenderType is the kind of originator a message is sent from.
+type SenderType string
+
+const (
+	SenderAlphanumeric SenderType = "alphanumeric" // Brand name, e.g. "ACME"; one-way
+	SenderLongCode     SenderType = "long_code"    // Regular phone number in E.164 form
+	SenderShortCode    SenderType = "short_code"   // Short number valid within one country
+)
+
+var (
+	alphanumericSender = regexp.MustCompile(`^[A-Za-z0-9 ]{1,11}$`)
+	shortCodeSender    = regexp.MustCompile(`^[0-9]{3,8}$`)
+	countryCode        = regexp.MustCompile(`^[1-9][0-9]{0,3}$`)
+)
+
+// Sender errors
+var (
+	ErrInvalidSender    = errors.New("invalid sender")
+	ErrSenderNotFound   = errors.New("sender not found")
+	ErrSenderExists     = errors.New("sender already registered")
+	ErrSenderNotAllowed = errors.New("sender not allowed for recipient country")
+)
+
+// SenderID is a registered originator that broadcasts may send from. It may
+// only send to numbers whose E.164 country calling code is listed in
+// CountryCodes.
+type SenderID struct {
+	Sender       string     `gorm:"type:text;primaryKey"`
+	Type         SenderType `gorm:"type:text;not null"`
+	CountryCodes []string   `gorm:"type:jsonb;serializer:json;not null;default:'[]'"` // Calling codes without "+", e.g. "66"
+	Description  string     `gorm:"type:text;not null;default:''"`
+	CreatedAt    time.Time  `gorm:"not null"`
+	UpdatedAt    time.Time  `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (SenderID) TableName() string {
+	return "sender_ids"
+}
+
+// Validate checks the sender against the format rules of its type and the
+// country codes for well-formedness. Long codes are normalized to E.164.
+func (s *SenderID) Validate() error {
+	switch s.Type {
+	case SenderAlphanumeric:
+		if !alphanumericSender.MatchString(s.Sender) || strings.Trim(s.Sender, "0123456789 ") == "" {
+			return fmt.Errorf("%w: alphanumeric senders are 1-11 letters, digits or spaces with at least one letter", ErrInvalidSender)
+		}
+	case SenderLongCode:
+		phone, err := NormalizePhone(s.Sender)
+		if err != nil {
+			return fmt.Errorf("%w: long codes must be E.164 numbers", ErrInvalidSender)
+		}
+		s.Sender = phone
+	case SenderShortCode:
+		if !shortCodeSender.MatchString(s.Sender) {
+			return fmt.Errorf("%w: short codes are 3-8 digits", ErrInvalidSender)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidSender, s.Type)
+	}
+
+	if len(s.CountryCodes) == 0 {
+		return fmt.Errorf("%w: at least one country code is required", ErrInvalidSender)
+	}
+	for i, code := range s.CountryCodes {
+		code = strings.TrimPrefix(strings.TrimSpace(code), "+")
+		if !countryCode.MatchString(code) {
+			return fmt.Errorf("%w: %q is not a country calling code", ErrInvalidSender, s.CountryCodes[i])
+		}
+		s.CountryCodes[i] = code
+	}
+	if s.Type == SenderShortCode && len(s.CountryCodes) > 1 {
+		return fmt.Errorf("%w: a short code belongs to a single country", ErrInvalidSender)
+	}
+	return nil
+}
+
+// Allows reports whether the sender may send to phone, an E.164 number.
+func (s SenderID) Allows(phone string) bool {
+	digits := strings.TrimPrefix(phone, "+")
+	for _, code := range s.CountryCodes {
+		if strings.HasPrefix(digits, code) {
+			return true
+		}
+	}
+	return false
+}
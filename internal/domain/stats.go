@@ -0,0 +1,68 @@
+package domain
+
+// BroadcastStats counts a broadcast's messages by status, and the clicks on
+// its short links.
+type BroadcastStats struct {
+	Total      int
+	Pending    int
+	Queued     int
+	Sent       int // Accepted by the provider, no receipt yet
+	Delivered  int
+	Failed     int
+	Suppressed int
+
+	Clicks          int // Every recorded click, repeat clicks included
+	ClickedMessages int // Messages whose links were clicked at least once
+}
+
+// Add counts n messages in status.
+func (s *BroadcastStats) Add(status Status, n int) {
+	s.Total += n
+	switch status {
+	case StatusPending:
+		s.Pending += n
+	case StatusQueued:
+		s.Queued += n
+	case StatusSent:
+		s.Sent += n
+	case StatusDelivered:
+		s.Delivered += n
+	case StatusFailed:
+		s.Failed += n
+	case StatusSuppressed:
+		s.Suppressed += n
+	}
+}
+
+// Merge adds the counts of other.
+func (s *BroadcastStats) Merge(other BroadcastStats) {
+	s.Total += other.Total
+	s.Pending += other.Pending
+	s.Queued += other.Queued
+	s.Sent += other.Sent
+	s.Delivered += other.Delivered
+	s.Failed += other.Failed
+	s.Suppressed += other.Suppressed
+	s.Clicks += other.Clicks
+	s.ClickedMessages += other.ClickedMessages
+}
+
+// DeliveryRate is the share of messages handed to the provider that were
+// confirmed delivered, between 0 and 1.
+func (s BroadcastStats) DeliveryRate() float64 {
+	return ratio(s.Delivered, s.Sent+s.Delivered+s.Failed)
+}
+
+// ClickThroughRate is the share of messages accepted by the provider whose
+// links were clicked, between 0 and 1. Sent messages count as well as
+// delivered ones, since not every provider reports deliveries.
+func (s BroadcastStats) ClickThroughRate() float64 {
+	return ratio(s.ClickedMessages, s.Sent+s.Delivered)
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Suppression sources, recording how a number ended up on the list.
+const (
+	SuppressionSourceAPI    = "api"
+	SuppressionSourceImport = "import"
+)
+
+// ErrorCodeOptedOut marks a message suppressed at send time because its
+// recipient was added to the suppression list after the message was created.
+const ErrorCodeOptedOut = "OPTED_OUT"
+
+// Suppression blocks messages to a phone number, either from every sender
+// (Sender empty) or from one sender ID only.
+type Suppression struct {
+	Phone     string    `gorm:"type:text;primaryKey"`
+	Sender    string    `gorm:"type:text;primaryKey;default:''"` // Empty for the global list
+	Reason    string    `gorm:"type:text"`
+	Source    string    `gorm:"type:text;not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (Suppression) TableName() string {
+	return "suppressions"
+}
+
+// Suppression errors
+var (
+	ErrSuppressionNotFound = errors.New("suppression not found")
+	ErrRecipientSuppressed = errors.New("recipient is on the suppression list")
+)
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxVariants bounds the number of variants in one A/B test.
+const maxVariants = 10
+
+// ErrInvalidVariants is returned for a malformed set of A/B test variants.
+var ErrInvalidVariants = errors.New("invalid variants")
+
+// Variant is one message body of an A/B test within a broadcast. About
+// Percent percent of the recipients get its Body.
+type Variant struct {
+	Name    string `json:"name"`
+	Body    string `json:"body"`
+	Percent int    `json:"percent"`
+}
+
+// ValidateVariants checks that there are 2 to 10 variants with distinct
+// names and non-empty bodies whose percentages add up to 100. Names are
+// trimmed of surrounding space.
+func ValidateVariants(variants []Variant) error {
+	if len(variants) < 2 || len(variants) > maxVariants {
+		return fmt.Errorf("%w: between 2 and %d variants are required", ErrInvalidVariants, maxVariants)
+	}
+
+	names := make(map[string]bool, len(variants))
+	total := 0
+	for i := range variants {
+		v := &variants[i]
+		v.Name = strings.TrimSpace(v.Name)
+		switch {
+		case v.Name == "" || len(v.Name) > 32:
+			return fmt.Errorf("%w: variant names are 1-32 characters", ErrInvalidVariants)
+		case names[v.Name]:
+			return fmt.Errorf("%w: duplicate name %q", ErrInvalidVariants, v.Name)
+		case v.Body == "":
+			return fmt.Errorf("%w: variant %q has no body", ErrInvalidVariants, v.Name)
+		case v.Percent <= 0:
+			return fmt.Errorf("%w: variant %q needs a percent above 0", ErrInvalidVariants, v.Name)
+		}
+		names[v.Name] = true
+		total += v.Percent
+	}
+	if total != 100 {
+		return fmt.Errorf("%w: percentages add up to %d, not 100", ErrInvalidVariants, total)
+	}
+	return nil
+}
+
+// AssignVariant picks the variant for a recipient of a broadcast from a
+// validated, non-empty set. The choice depends only on the broadcast and the
+// number. A job's broadcast ID is derived from the job (see JobBroadcastID),
+// so a retried job assigns every recipient the same variant as the first run.
+func AssignVariant(variants []Variant, broadcastID uuid.UUID, phone string) Variant {
+	h := sha256.New()
+	h.Write(broadcastID[:])
+	h.Write([]byte(phone))
+	bucket := int(binary.BigEndian.Uint64(h.Sum(nil)[:8]) % 100)
+
+	for _, v := range variants {
+		if bucket < v.Percent {
+			return v
+		}
+		bucket -= v.Percent
+	}
+	return variants[len(variants)-1]
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAssignVariantDistribution(t *testing.T) {
+	const recipients = 20000
+	broadcastID := uuid.MustParse("6f1c2d3e-0000-4000-8000-000000000001")
+
+	tests := []struct {
+		name     string
+		variants []Variant
+	}{
+		{"even split", []Variant{{Name: "A", Percent: 50}, {Name: "B", Percent: 50}}},
+		{"uneven split", []Variant{{Name: "A", Percent: 10}, {Name: "B", Percent: 90}}},
+		{"three variants", []Variant{{Name: "A", Percent: 33}, {Name: "B", Percent: 33}, {Name: "C", Percent: 34}}},
+		{"one percent variant", []Variant{{Name: "A", Percent: 1}, {Name: "B", Percent: 49}, {Name: "C", Percent: 50}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts := make(map[string]int)
+			for i := 0; i < recipients; i++ {
+				phone := fmt.Sprintf("+6681%07d", i)
+				counts[AssignVariant(tt.variants, broadcastID, phone).Name]++
+			}
+
+			for _, v := range tt.variants {
+				got := float64(counts[v.Name]) * 100 / recipients
+				if math.Abs(got-float64(v.Percent)) > 1.5 {
+					t.Errorf("variant %s got %.1f%% of recipients, want %d%% ± 1.5", v.Name, got, v.Percent)
+				}
+			}
+		})
+	}
+}
+
+func TestAssignVariantIsStable(t *testing.T) {
+	variants := []Variant{{Name: "A", Percent: 50}, {Name: "B", Percent: 50}}
+	first := uuid.MustParse("6f1c2d3e-0000-4000-8000-000000000001")
+	second := uuid.MustParse("6f1c2d3e-0000-4000-8000-000000000002")
+
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		phone := fmt.Sprintf("+6681%07d", i)
+		v := AssignVariant(variants, first, phone)
+		if again := AssignVariant(variants, first, phone); again.Name != v.Name {
+			t.Fatalf("%s got %s, then %s for the same broadcast", phone, v.Name, again.Name)
+		}
+		if AssignVariant(variants, second, phone).Name != v.Name {
+			moved++
+		}
+	}
+
+	// Another broadcast reshuffles recipients instead of always putting the
+	// same numbers in the same variant.
+	if moved < 400 || moved > 600 {
+		t.Errorf("%d of 1000 recipients changed variant in another broadcast, want about 500", moved)
+	}
+}
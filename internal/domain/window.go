@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // The alpine runtime image ships without a zone database
+)
+
+// ErrInvalidWindow is returned for a malformed delivery window.
+var ErrInvalidWindow = errors.New("invalid delivery window")
+
+// ErrNoTimeZone is returned when a delivery window cannot be applied to a
+// recipient: the number's country code has no known zone, or the window
+// never opens in every zone of a country spanning several.
+var ErrNoTimeZone = errors.New("no time zone for recipient")
+
+// ErrorCodeNoTimeZone marks a message suppressed because its delivery window
+// could not be applied to the recipient.
+const ErrorCodeNoTimeZone = "NO_TIME_ZONE"
+
+// TimeZoneAttribute is the contact attribute (and CSV column) naming the
+// recipient's IANA time zone, e.g. "Asia/Bangkok". It overrides the zone
+// inferred from the phone number's country code.
+const TimeZoneAttribute = "timezone"
+
+// DeliveryWindow is the time of day, in the recipient's local time, during
+// which messages of a broadcast may be published. Start and End are minutes
+// after midnight; a window whose End is before its Start spans midnight.
+// The zero value allows any time.
+type DeliveryWindow struct {
+	Start int `gorm:"not null;default:0"`
+	End   int `gorm:"not null;default:0"`
+}
+
+// ParseDeliveryWindow parses a window from "HH:MM" start and end times.
+// Both empty yields the zero window.
+func ParseDeliveryWindow(start, end string) (DeliveryWindow, error) {
+	if start == "" && end == "" {
+		return DeliveryWindow{}, nil
+	}
+
+	s, err := parseClock(start)
+	if err != nil {
+		return DeliveryWindow{}, err
+	}
+	e, err := parseClock(end)
+	if err != nil {
+		return DeliveryWindow{}, err
+	}
+	if s == e {
+		return DeliveryWindow{}, fmt.Errorf("%w: start and end are both %s", ErrInvalidWindow, start)
+	}
+	return DeliveryWindow{Start: s, End: e}, nil
+}
+
+// parseClock converts "HH:MM" (24-hour) into minutes after midnight.
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a HH:MM time", ErrInvalidWindow, clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// IsZero reports whether the window allows any time.
+func (w DeliveryWindow) IsZero() bool {
+	return w.Start == w.End
+}
+
+// Allows reports whether local time t falls inside the window.
+func (w DeliveryWindow) Allows(t time.Time) bool {
+	if w.IsZero() {
+		return true
+	}
+	m := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return m >= w.Start && m < w.End
+	}
+	return m >= w.Start || m < w.End
+}
+
+// NextOpen returns t if the window allows it, otherwise the next time the
+// window opens, in t's location.
+func (w DeliveryWindow) NextOpen(t time.Time) time.Time {
+	if w.Allows(t) {
+		return t
+	}
+	open := time.Date(t.Year(), t.Month(), t.Day(), w.Start/60, w.Start%60, 0, 0, t.Location())
+	if !open.After(t) {
+		open = time.Date(t.Year(), t.Month(), t.Day()+1, w.Start/60, w.Start%60, 0, 0, t.Location())
+	}
+	return open
+}
+
+// String formats the window as "HH:MM-HH:MM", or "" for the zero window.
+func (w DeliveryWindow) String() string {
+	if w.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// AllowsIn reports whether t falls inside the window in every one of locs.
+func (w DeliveryWindow) AllowsIn(t time.Time, locs []*time.Location) bool {
+	for _, loc := range locs {
+		if !w.Allows(t.In(loc)) {
+			return false
+		}
+	}
+	return true
+}
+
+// NextOpenIn returns the first time from t on that the window allows in
+// every one of locs. It reports false when the window never opens in all of
+// them at once, as a short window does across zones many hours apart.
+func (w DeliveryWindow) NextOpenIn(t time.Time, locs []*time.Location) (time.Time, bool) {
+	// Each step moves to where the window next opens in a zone that is still
+	// closed, which never passes the earliest time open everywhere. Two days
+	// cover every offset between zones and a DST change.
+	limit := t.Add(48 * time.Hour)
+	for !t.After(limit) {
+		next := t
+		for _, loc := range locs {
+			if open := w.NextOpen(t.In(loc)); open.After(next) {
+				next = open
+			}
+		}
+		if next.Equal(t) {
+			return t, true
+		}
+		t = next
+	}
+	return time.Time{}, false
+}
+
+// CheckRecipient returns an error wrapping ErrNoTimeZone if the window
+// cannot be applied to a recipient with the given explicit zone and number.
+func (w DeliveryWindow) CheckRecipient(zone, phone string) error {
+	if w.IsZero() {
+		return nil
+	}
+	locs, err := RecipientLocations(zone, phone)
+	if err != nil {
+		return err
+	}
+	if _, ok := w.NextOpenIn(time.Now(), locs); !ok {
+		return fmt.Errorf("%w: window %s never opens in every zone of %s's country; set the %s attribute", ErrNoTimeZone, w, phone, TimeZoneAttribute)
+	}
+	return nil
+}
+
+// countryZones maps E.164 country calling codes to their time zones. For
+// countries spanning several it lists the easternmost and westernmost
+// zones: without the timezone attribute, a window must be open in both, so
+// it is open everywhere in between.
+var countryZones = map[string][]string{
+	"1":   {"America/St_Johns", "Pacific/Honolulu"},
+	"7":   {"Asia/Kamchatka", "Europe/Kaliningrad"},
+	"20":  {"Africa/Cairo"},
+	"27":  {"Africa/Johannesburg"},
+	"30":  {"Europe/Athens"},
+	"31":  {"Europe/Amsterdam"},
+	"32":  {"Europe/Brussels"},
+	"33":  {"Europe/Paris"},
+	"34":  {"Europe/Madrid"},
+	"36":  {"Europe/Budapest"},
+	"39":  {"Europe/Rome"},
+	"40":  {"Europe/Bucharest"},
+	"41":  {"Europe/Zurich"},
+	"43":  {"Europe/Vienna"},
+	"44":  {"Europe/London"},
+	"45":  {"Europe/Copenhagen"},
+	"46":  {"Europe/Stockholm"},
+	"47":  {"Europe/Oslo"},
+	"48":  {"Europe/Warsaw"},
+	"49":  {"Europe/Berlin"},
+	"51":  {"America/Lima"},
+	"52":  {"America/Cancun", "America/Tijuana"},
+	"54":  {"America/Argentina/Buenos_Aires"},
+	"55":  {"America/Noronha", "America/Rio_Branco"},
+	"56":  {"America/Santiago"},
+	"57":  {"America/Bogota"},
+	"60":  {"Asia/Kuala_Lumpur"},
+	"61":  {"Australia/Sydney", "Australia/Perth"},
+	"62":  {"Asia/Jayapura", "Asia/Jakarta"},
+	"63":  {"Asia/Manila"},
+	"64":  {"Pacific/Auckland"},
+	"65":  {"Asia/Singapore"},
+	"66":  {"Asia/Bangkok"},
+	"81":  {"Asia/Tokyo"},
+	"82":  {"Asia/Seoul"},
+	"84":  {"Asia/Ho_Chi_Minh"},
+	"86":  {"Asia/Shanghai"},
+	"90":  {"Europe/Istanbul"},
+	"91":  {"Asia/Kolkata"},
+	"92":  {"Asia/Karachi"},
+	"94":  {"Asia/Colombo"},
+	"95":  {"Asia/Yangon"},
+	"98":  {"Asia/Tehran"},
+	"234": {"Africa/Lagos"},
+	"254": {"Africa/Nairobi"},
+	"351": {"Europe/Lisbon"},
+	"353": {"Europe/Dublin"},
+	"358": {"Europe/Helsinki"},
+	"852": {"Asia/Hong_Kong"},
+	"853": {"Asia/Macau"},
+	"855": {"Asia/Phnom_Penh"},
+	"856": {"Asia/Vientiane"},
+	"880": {"Asia/Dhaka"},
+	"886": {"Asia/Taipei"},
+	"966": {"Asia/Riyadh"},
+	"971": {"Asia/Dubai"},
+	"972": {"Asia/Jerusalem"},
+}
+
+// zoneCache holds loaded locations; time.LoadLocation reads the zone
+// database on every call.
+var zoneCache sync.Map
+
+// LoadZone returns the named IANA time zone, reporting false for unknown names.
+func LoadZone(name string) (*time.Location, bool) {
+	if name == "" {
+		return nil, false
+	}
+	if loc, ok := zoneCache.Load(name); ok {
+		return loc.(*time.Location), true
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	zoneCache.Store(name, loc)
+	return loc, true
+}
+
+// RecipientLocations returns the time zones to apply delivery windows in:
+// the explicit zone when it is valid, else the zones of the phone number's
+// country code. It returns ErrNoTimeZone for a country code it does not know.
+func RecipientLocations(zone, phone string) ([]*time.Location, error) {
+	if loc, ok := LoadZone(zone); ok {
+		return []*time.Location{loc}, nil
+	}
+
+	digits := strings.TrimPrefix(phone, "+")
+	for n := 3; n >= 1; n-- {
+		if len(digits) < n {
+			continue
+		}
+		names, ok := countryZones[digits[:n]]
+		if !ok {
+			continue
+		}
+		locs := make([]*time.Location, 0, len(names))
+		for _, name := range names {
+			if loc, ok := LoadZone(name); ok {
+				locs = append(locs, loc)
+			}
+		}
+		if len(locs) > 0 {
+			return locs, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown country code of %s; set the %s attribute", ErrNoTimeZone, phone, TimeZoneAttribute)
+}
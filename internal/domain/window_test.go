@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, ok := LoadZone(name)
+	if !ok {
+		t.Fatalf("unknown zone %q", name)
+	}
+	return loc
+}
+
+func TestDeliveryWindowAllows(t *testing.T) {
+	day := DeliveryWindow{Start: 8 * 60, End: 20 * 60}
+	night := DeliveryWindow{Start: 22 * 60, End: 6 * 60}
+	at := func(hour, min int) time.Time { return time.Date(2026, 1, 15, hour, min, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		window DeliveryWindow
+		t      time.Time
+		want   bool
+	}{
+		{"zero window allows any time", DeliveryWindow{}, at(3, 0), true},
+		{"day window at start", day, at(8, 0), true},
+		{"day window before start", day, at(7, 59), false},
+		{"day window before end", day, at(19, 59), true},
+		{"day window at end", day, at(20, 0), false},
+		{"overnight window before midnight", night, at(23, 0), true},
+		{"overnight window at midnight", night, at(0, 0), true},
+		{"overnight window before end", night, at(5, 59), true},
+		{"overnight window at end", night, at(6, 0), false},
+		{"overnight window midday", night, at(12, 0), false},
+		{"overnight window before start", night, at(21, 59), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Allows(tt.t); got != tt.want {
+				t.Errorf("Allows(%s) = %v, want %v", tt.t.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliveryWindowNextOpen(t *testing.T) {
+	day := DeliveryWindow{Start: 8 * 60, End: 20 * 60}
+	night := DeliveryWindow{Start: 22 * 60, End: 6 * 60}
+	newYork := mustZone(t, "America/New_York")
+
+	tests := []struct {
+		name   string
+		window DeliveryWindow
+		t      time.Time
+		want   time.Time
+	}{
+		{"open now", day, time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC), time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"later today", day, time.Date(2026, 1, 15, 7, 0, 0, 0, time.UTC), time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC)},
+		{"tomorrow", day, time.Date(2026, 1, 15, 20, 0, 0, 0, time.UTC), time.Date(2026, 1, 16, 8, 0, 0, 0, time.UTC)},
+		{"across month end", day, time.Date(2026, 1, 31, 21, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC)},
+		{"overnight window opens tonight", night, time.Date(2026, 1, 15, 6, 30, 0, 0, time.UTC), time.Date(2026, 1, 15, 22, 0, 0, 0, time.UTC)},
+		{"overnight window open after midnight", night, time.Date(2026, 1, 16, 1, 0, 0, 0, time.UTC), time.Date(2026, 1, 16, 1, 0, 0, 0, time.UTC)},
+		// 2026-03-08 02:00 EST jumps to 03:00 EDT: 08:00 local is 12:00 UTC.
+		{"across spring forward", day, time.Date(2026, 3, 7, 21, 0, 0, 0, newYork), time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)},
+		// 2026-11-01 02:00 EDT falls back to 01:00 EST: 08:00 local is 13:00 UTC.
+		{"across fall back", day, time.Date(2026, 10, 31, 22, 0, 0, 0, newYork), time.Date(2026, 11, 1, 13, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.window.NextOpen(tt.t)
+			if !got.Equal(tt.want) {
+				t.Errorf("NextOpen(%s) = %s, want %s", tt.t, got, tt.want)
+			}
+			if got.Location() != tt.t.Location() {
+				t.Errorf("location = %s, want %s", got.Location(), tt.t.Location())
+			}
+		})
+	}
+}
+
+func TestDeliveryWindowNextOpenIn(t *testing.T) {
+	day := DeliveryWindow{Start: 8 * 60, End: 20 * 60}
+	northAmerica, err := RecipientLocations("", "+12125550100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	russia, err := RecipientLocations("", "+74951234567")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		window DeliveryWindow
+		locs   []*time.Location
+		t      time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		// In January 08:00-20:00 is 11:30-23:30 UTC in St. John's and
+		// 18:00-06:00 UTC in Honolulu.
+		{"open in both zones", day, northAmerica, time.Date(2026, 1, 15, 19, 0, 0, 0, time.UTC), time.Date(2026, 1, 15, 19, 0, 0, 0, time.UTC), true},
+		{"open in the east only", day, northAmerica, time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 15, 18, 0, 0, 0, time.UTC), true},
+		{"open in the west only", day, northAmerica, time.Date(2026, 1, 15, 23, 45, 0, 0, time.UTC), time.Date(2026, 1, 16, 18, 0, 0, 0, time.UTC), true},
+		{"short window never open in both", DeliveryWindow{Start: 9 * 60, End: 10 * 60}, russia, time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC), time.Time{}, false},
+		{"single zone", day, []*time.Location{mustZone(t, "Asia/Bangkok")}, time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 15, 1, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.window.NextOpenIn(tt.t, tt.locs)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("NextOpenIn(%s) = %s, want %s", tt.t, got.UTC(), tt.want)
+			}
+			if ok && !tt.window.AllowsIn(got, tt.locs) {
+				t.Errorf("AllowsIn(%s) = false for the returned time", got.UTC())
+			}
+		})
+	}
+}
+
+func TestRecipientLocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		zone    string
+		phone   string
+		want    []string
+		wantErr error
+	}{
+		{"explicit zone", "Asia/Tokyo", "+66812345678", []string{"Asia/Tokyo"}, nil},
+		{"invalid explicit zone uses the country", "Mars/Olympus", "+66812345678", []string{"Asia/Bangkok"}, nil},
+		{"three digit code", "", "+85291234567", []string{"Asia/Hong_Kong"}, nil},
+		{"multi-zone country", "", "+61412345678", []string{"Australia/Sydney", "Australia/Perth"}, nil},
+		{"unknown country code", "", "+999123456", nil, ErrNoTimeZone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locs, err := RecipientLocations(tt.zone, tt.phone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if len(locs) != len(tt.want) {
+				t.Fatalf("zones = %v, want %v", locs, tt.want)
+			}
+			for i, loc := range locs {
+				if loc.String() != tt.want[i] {
+					t.Errorf("zone %d = %s, want %s", i, loc, tt.want[i])
+				}
+			}
+		})
+	}
+}
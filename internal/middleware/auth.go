@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BearerToken rejects requests whose Authorization header does not carry
+// "Bearer <token>". An empty token rejects every request.
+func BearerToken(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		got, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return unauthorized(c, "invalid or missing bearer token")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit answers request bodies larger than limit bytes with 413. routes
+// sets a different limit for single routes, keyed by "METHOD /path".
+//
+// It is meant for apps with StreamRequestBody enabled: fasthttp then streams
+// any body above its own BodyLimit to the handler instead of refusing it, and
+// this check rejects an oversized body from its Content-Length before it is
+// read. Chunked bodies have no length up front; they are read here, up to the
+// limit.
+func BodyLimit(limit int, routes map[string]int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		max := limit
+		if n, ok := routes[c.Method()+" "+c.Path()]; ok {
+			max = n
+		}
+
+		req := c.Request()
+		switch n := req.Header.ContentLength(); {
+		case n > max:
+			return entityTooLarge(c)
+		case n == -1:
+			var body io.Reader = bytes.NewReader(req.Body())
+			if stream := req.BodyStream(); stream != nil {
+				body = stream
+			}
+
+			buf, err := io.ReadAll(io.LimitReader(body, int64(max)+1))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot read request body"})
+			}
+			if len(buf) > max {
+				return entityTooLarge(c)
+			}
+			req.SetBody(buf)
+		}
+
+		return c.Next()
+	}
+}
+
+func entityTooLarge(c *fiber.Ctx) error {
+	// The rest of the body is still unread on the connection.
+	c.Context().SetConnectionClose()
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": "request body too large"})
+}
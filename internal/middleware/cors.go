@@ -18,7 +18,7 @@ func CORSConfig() fiber.Handler {
 
 		// AllowHeaders: Specify allowed request headers
 		// OWASP: Whitelist only required headers
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization,X-Request-ID",
+		AllowHeaders: "Origin,Content-Type,Accept,Authorization,X-Request-ID,Idempotency-Key",
 
 		// AllowCredentials: Enable if using cookies/auth
 		// OWASP: Only enable if necessary and with specific origins
@@ -26,7 +26,7 @@ func CORSConfig() fiber.Handler {
 
 		// ExposeHeaders: Headers that client can access
 		// OWASP: Only expose necessary headers
-		ExposeHeaders: "Content-Length,X-Request-ID",
+		ExposeHeaders: "Content-Length,X-Request-ID,Idempotent-Replayed",
 
 		// MaxAge: Cache preflight requests (in seconds)
 		// OWASP: Reasonable cache time to reduce preflight requests
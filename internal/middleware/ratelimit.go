@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"strconv"
 	"sync"
 	"time"
 
@@ -49,23 +50,61 @@ func (rl *RateLimiter) Middleware() fiber.Handler {
 		}
 
 		if !rl.allow(ip) {
-			c.Set("X-RateLimit-Limit", string(rune(rl.rate)))
-			c.Set("X-RateLimit-Remaining", "0")
-			c.Set("Retry-After", string(rune(int(rl.window.Seconds()))))
-
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error":       "rate limit exceeded",
-				"message":     "Too many requests. Please try again later.",
-				"retry_after": int(rl.window.Seconds()),
-			})
+			return rl.reject(c)
 		}
 
 		return c.Next()
 	}
 }
 
-// allow checks if request is allowed based on rate limit
+// FailureMiddleware returns a Fiber middleware handler that only counts
+// requests answered with status, e.g. 404 for a guessed short-link code,
+// against the limit. A client that used up its allowance is refused every
+// request until it refills.
+func (rl *RateLimiter) FailureMiddleware(status int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ip := c.IP()
+
+		if c.Path() == "/health" {
+			return c.Next()
+		}
+
+		if !rl.available(ip) {
+			return rl.reject(c)
+		}
+
+		err := c.Next()
+		if c.Response().StatusCode() == status {
+			rl.allow(ip)
+		}
+		return err
+	}
+}
+
+// reject answers a request over the limit with 429.
+func (rl *RateLimiter) reject(c *fiber.Ctx) error {
+	c.Set("X-RateLimit-Limit", strconv.Itoa(rl.rate))
+	c.Set("X-RateLimit-Remaining", "0")
+	c.Set("Retry-After", strconv.Itoa(int(rl.window.Seconds())))
+
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"error":       "rate limit exceeded",
+		"message":     "Too many requests. Please try again later.",
+		"retry_after": int(rl.window.Seconds()),
+	})
+}
+
+// allow checks if request is allowed based on rate limit, spending a token
 func (rl *RateLimiter) allow(ip string) bool {
+	return rl.spend(ip, true)
+}
+
+// available reports whether ip has a token left without spending it
+func (rl *RateLimiter) available(ip string) bool {
+	return rl.spend(ip, false)
+}
+
+func (rl *RateLimiter) spend(ip string, take bool) bool {
 	rl.mu.Lock()
 	visitor, exists := rl.visitors[ip]
 	if !exists {
@@ -101,7 +140,9 @@ func (rl *RateLimiter) allow(ip string) bool {
 
 	// Check if request is allowed
 	if visitor.tokens > 0 {
-		visitor.tokens--
+		if take {
+			visitor.tokens--
+		}
 		return true
 	}
 
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Headers carrying the webhook signature.
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+	SignatureNonceHeader     = "X-Signature-Nonce"
+)
+
+// maxNonceLength bounds the nonce kept in the replay cache.
+const maxNonceLength = 128
+
+// SignatureConfig configures HMAC verification of inbound webhooks.
+type SignatureConfig struct {
+	// Secrets maps a provider name to its shared signing secret.
+	Secrets map[string]string
+
+	// DefaultProvider is used when the route has no :provider parameter.
+	DefaultProvider string
+
+	// Tolerance is the maximum allowed clock skew between the signer and us.
+	// Requests with a timestamp outside this window are rejected as replays.
+	Tolerance time.Duration
+}
+
+// Sign computes the hex-encoded HMAC-SHA256 of "<timestamp>.<nonce>.<body>".
+// Providers (and our mock) use it to sign callbacks. The nonce is unique per
+// request, so two identical events sent within the same second still carry
+// different signatures.
+func Sign(secret string, timestamp int64, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature rejects webhook requests that are not signed with the
+// provider's shared secret, fall outside the timestamp window, or replay a
+// nonce that was already accepted.
+func VerifySignature(cfg SignatureConfig) fiber.Handler {
+	if cfg.Tolerance <= 0 {
+		cfg.Tolerance = 5 * time.Minute
+	}
+	seen := newReplayCache(cfg.Tolerance)
+
+	return func(c *fiber.Ctx) error {
+		provider := c.Params("provider")
+		if provider == "" {
+			provider = cfg.DefaultProvider
+		}
+
+		secret, ok := cfg.Secrets[provider]
+		if !ok || secret == "" {
+			return unauthorized(c, "unknown provider")
+		}
+
+		signature := c.Get(SignatureHeader)
+		nonce := c.Get(SignatureNonceHeader)
+		ts, err := strconv.ParseInt(c.Get(SignatureTimestampHeader), 10, 64)
+		if signature == "" || nonce == "" || len(nonce) > maxNonceLength || err != nil {
+			return unauthorized(c, "missing signature")
+		}
+
+		// OWASP: reject stale or future-dated requests to limit replay attacks
+		skew := time.Since(time.Unix(ts, 0))
+		if skew > cfg.Tolerance || skew < -cfg.Tolerance {
+			return unauthorized(c, "signature timestamp outside allowed window")
+		}
+
+		expected := Sign(secret, ts, nonce, c.Body())
+		if !hmac.Equal([]byte(signature), []byte(expected)) {
+			return unauthorized(c, "invalid signature")
+		}
+
+		if !seen.add(provider + ":" + nonce) {
+			return unauthorized(c, "replayed request")
+		}
+
+		c.Locals("provider", provider)
+		return c.Next()
+	}
+}
+
+func unauthorized(c *fiber.Ctx, msg string) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": msg})
+}
+
+// replayCache remembers accepted nonces for the length of the timestamp
+// window; older entries can never pass the timestamp check again.
+type replayCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	ttl     time.Duration
+}
+
+func newReplayCache(ttl time.Duration) *replayCache {
+	rc := &replayCache{entries: make(map[string]time.Time), ttl: ttl}
+	go rc.cleanup()
+	return rc
+}
+
+// add records key and reports whether it was not already present.
+func (rc *replayCache) add(key string) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if _, exists := rc.entries[key]; exists {
+		return false
+	}
+	rc.entries[key] = time.Now()
+	return true
+}
+
+// cleanup evicts entries older than twice the window
+func (rc *replayCache) cleanup() {
+	ticker := time.NewTicker(rc.ttl)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		rc.mu.Lock()
+		now := time.Now()
+		for key, at := range rc.entries {
+			if now.Sub(at) > rc.ttl*2 {
+				delete(rc.entries, key)
+			}
+		}
+		rc.mu.Unlock()
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"provider_id":"p-1","status":"delivered"}`)
+	base := Sign("secret", 1700000000, "n-1", body)
+
+	tests := []struct {
+		name  string
+		sig   string
+		equal bool
+	}{
+		{"same input", Sign("secret", 1700000000, "n-1", body), true},
+		{"other secret", Sign("other", 1700000000, "n-1", body), false},
+		{"other timestamp", Sign("secret", 1700000001, "n-1", body), false},
+		{"other nonce", Sign("secret", 1700000000, "n-2", body), false},
+		{"other body", Sign("secret", 1700000000, "n-1", []byte(`{}`)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.sig == base) != tt.equal {
+				t.Errorf("signature equal = %v, want %v", tt.sig == base, tt.equal)
+			}
+		})
+	}
+
+	if len(base) != 64 {
+		t.Errorf("signature length = %d, want 64 hex characters", len(base))
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "secret"
+	body := `{"provider_id":"p-1","status":"delivered"}`
+	now := time.Now().Unix()
+
+	app := fiber.New()
+	app.Post("/dlr/:provider?", VerifySignature(SignatureConfig{
+		Secrets:         map[string]string{"mock": secret},
+		DefaultProvider: "mock",
+		Tolerance:       time.Minute,
+	}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusAccepted)
+	})
+
+	type request struct {
+		path      string
+		timestamp string
+		nonce     string
+		signature string
+	}
+	signed := func(ts int64, nonce string) request {
+		return request{"/dlr", strconv.FormatInt(ts, 10), nonce, Sign(secret, ts, nonce, []byte(body))}
+	}
+
+	tests := []struct {
+		name string
+		req  request
+		want int
+	}{
+		{"valid", signed(now, "n-1"), fiber.StatusAccepted},
+		{"same body and second, new nonce", signed(now, "n-2"), fiber.StatusAccepted},
+		{"replayed nonce", signed(now, "n-1"), fiber.StatusUnauthorized},
+		{"named provider", request{"/dlr/mock", strconv.FormatInt(now, 10), "n-3", Sign(secret, now, "n-3", []byte(body))}, fiber.StatusAccepted},
+		{"unknown provider", request{"/dlr/other", strconv.FormatInt(now, 10), "n-4", Sign(secret, now, "n-4", []byte(body))}, fiber.StatusUnauthorized},
+		{"missing nonce", signed(now, ""), fiber.StatusUnauthorized},
+		{"nonce too long", signed(now, strings.Repeat("n", maxNonceLength+1)), fiber.StatusUnauthorized},
+		{"missing signature", request{"/dlr", strconv.FormatInt(now, 10), "n-5", ""}, fiber.StatusUnauthorized},
+		{"bad timestamp", request{"/dlr", "yesterday", "n-6", Sign(secret, now, "n-6", []byte(body))}, fiber.StatusUnauthorized},
+		{"stale timestamp", signed(now-120, "n-7"), fiber.StatusUnauthorized},
+		{"future timestamp", signed(now+120, "n-8"), fiber.StatusUnauthorized},
+		{"wrong secret", request{"/dlr", strconv.FormatInt(now, 10), "n-9", Sign("other", now, "n-9", []byte(body))}, fiber.StatusUnauthorized},
+		{"nonce not signed", request{"/dlr", strconv.FormatInt(now, 10), "n-10", Sign(secret, now, "n-11", []byte(body))}, fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.req.path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(SignatureTimestampHeader, tt.req.timestamp)
+			if tt.req.nonce != "" {
+				req.Header.Set(SignatureNonceHeader, tt.req.nonce)
+			}
+			if tt.req.signature != "" {
+				req.Header.Set(SignatureHeader, tt.req.signature)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// CallbackRepository defines persistence operations for outbound status callbacks.
+type CallbackRepository interface {
+	// ClaimDueCallbacks leases up to limit events that are due for delivery.
+	// A claimed event becomes due again after lease if it is never recorded.
+	ClaimDueCallbacks(ctx context.Context, limit int, lease time.Duration) ([]domain.CallbackEvent, error)
+
+	// RecordCallbackAttempt appends to the delivery log and stores the event's new state.
+	RecordCallbackAttempt(ctx context.Context, event domain.CallbackEvent, attempt domain.CallbackAttempt) error
+
+	// GetCallback returns an event with its delivery log, oldest attempt first.
+	GetCallback(ctx context.Context, id uuid.UUID) (*domain.CallbackEvent, []domain.CallbackAttempt, error)
+
+	// ListCallbacks returns the events of a broadcast, newest first.
+	ListCallbacks(ctx context.Context, broadcastID uuid.UUID, limit int) ([]domain.CallbackEvent, error)
+
+	// ResetCallback makes an event due immediately with a fresh attempt budget.
+	ResetCallback(ctx context.Context, id uuid.UUID) error
+}
+
+// CallbackSender delivers a signed callback event to a client application.
+type CallbackSender interface {
+	// Send POSTs the event's payload to its URL and returns the HTTP status code.
+	Send(ctx context.Context, event domain.CallbackEvent) (int, error)
+}
@@ -0,0 +1,75 @@
+package ports
+
+import (
+	"context"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ContactFilter selects a page of contacts.
+type ContactFilter struct {
+	ListID *uuid.UUID // Only members of this list; nil for all contacts
+	Limit  int
+	Offset int
+}
+
+// Audience selects the contacts a broadcast targets: members of any of
+// ListIDs (all contacts when empty) that match Segment (all when nil).
+type Audience struct {
+	ListIDs []uuid.UUID
+	Segment domain.Segment
+}
+
+// ContactRepository defines persistence operations for contacts and lists.
+type ContactRepository interface {
+	// SaveContact inserts a new contact; a duplicate phone is ErrContactExists.
+	SaveContact(ctx context.Context, c domain.Contact) error
+
+	// UpdateContact replaces a contact's phone and attributes.
+	UpdateContact(ctx context.Context, c domain.Contact) error
+
+	// GetContact retrieves a contact by ID.
+	GetContact(ctx context.Context, id uuid.UUID) (*domain.Contact, error)
+
+	// ListContacts returns a page of contacts ordered by phone.
+	ListContacts(ctx context.Context, filter ContactFilter) ([]domain.Contact, error)
+
+	// DeleteContact removes a contact and its list memberships.
+	DeleteContact(ctx context.Context, id uuid.UUID) error
+
+	// UpsertContacts inserts contacts or merges their attributes into the
+	// existing contact with the same phone. It returns the contact IDs in
+	// input order. Phones must be unique within one call.
+	UpsertContacts(ctx context.Context, contacts []domain.Contact) ([]uuid.UUID, error)
+
+	// SaveList inserts a new contact list.
+	SaveList(ctx context.Context, l domain.ContactList) error
+
+	// UpdateList replaces a list's name and description.
+	UpdateList(ctx context.Context, l domain.ContactList) error
+
+	// GetList retrieves a list by ID with its member count.
+	GetList(ctx context.Context, id uuid.UUID) (*domain.ContactList, error)
+
+	// ListLists returns all lists with their member counts, by name.
+	ListLists(ctx context.Context) ([]domain.ContactList, error)
+
+	// DeleteList removes a list; its contacts are kept.
+	DeleteList(ctx context.Context, id uuid.UUID) error
+
+	// AddListMembers adds contacts to a list, ignoring existing members.
+	AddListMembers(ctx context.Context, listID uuid.UUID, contactIDs []uuid.UUID) error
+
+	// RemoveListMembers removes contacts from a list.
+	RemoveListMembers(ctx context.Context, listID uuid.UUID, contactIDs []uuid.UUID) error
+
+	// AudienceContacts returns the distinct contacts in the audience ordered
+	// by phone, at most limit of them unless limit is 0.
+	// It returns ErrListNotFound if any list does not exist.
+	AudienceContacts(ctx context.Context, audience Audience, limit int) ([]domain.Contact, error)
+
+	// CountAudience returns the number of distinct contacts in the audience.
+	CountAudience(ctx context.Context, audience Audience) (int64, error)
+}
@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// InboundRepository persists inbound (MO) messages.
+type InboundRepository interface {
+	// SaveInbound stores a new inbound message. A message whose provider ID
+	// was already stored is not inserted again; the stored row is returned
+	// instead, so redelivered webhooks are processed once.
+	SaveInbound(ctx context.Context, msg domain.InboundMessage) (*domain.InboundMessage, error)
+
+	// MarkInboundProcessed records the keyword outcome of an inbound message.
+	MarkInboundProcessed(ctx context.Context, id uuid.UUID, keyword string, action domain.KeywordAction) error
+
+	// LastOutboundMessage returns the newest message to phone that was handed
+	// to a provider before the given time, or domain.ErrMessageNotFound.
+	LastOutboundMessage(ctx context.Context, phone string, before time.Time) (*domain.Message, error)
+
+	// Conversation returns up to limit messages exchanged with phone before
+	// the given time, outbound and inbound merged, newest first.
+	Conversation(ctx context.Context, phone string, before time.Time, limit int) ([]domain.ConversationEntry, error)
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// JobRepository persists background broadcast creation jobs.
+type JobRepository interface {
+	// SaveJob inserts a new queued job and stores input, read to the end, as
+	// its chunks in the same transaction.
+	SaveJob(ctx context.Context, job domain.BroadcastJob, input io.Reader) error
+
+	// JobInput returns a reader over a job's stored input that loads one
+	// chunk at a time.
+	JobInput(ctx context.Context, id uuid.UUID) io.Reader
+
+	// ClaimJob marks the oldest queued job as running and returns it, or nil
+	// when there is none. Running jobs not updated within staleAfter are
+	// claimed again, so a crashed worker's job is retried.
+	ClaimJob(ctx context.Context, staleAfter time.Duration) (*domain.BroadcastJob, error)
+
+	// UpdateJobProgress stores the running totals of a job.
+	UpdateJobProgress(ctx context.Context, job domain.BroadcastJob) error
+
+	// TouchJob marks a running job as still alive without changing its totals.
+	TouchJob(ctx context.Context, id uuid.UUID) error
+
+	// FinishJob stores the final state of a job and deletes its input.
+	FinishJob(ctx context.Context, job domain.BroadcastJob) error
+
+	// GetJob retrieves a job.
+	GetJob(ctx context.Context, id uuid.UUID) (*domain.BroadcastJob, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"golang-sms-broadcast/internal/domain"
+)
+
+// LinkRepository resolves short links and records their clicks.
+type LinkRepository interface {
+	// GetShortLink returns the link for a code; an unknown one is ErrLinkNotFound.
+	GetShortLink(ctx context.Context, code string) (domain.ShortLink, error)
+
+	// RecordClick stores one visit to a short link.
+	RecordClick(ctx context.Context, click domain.LinkClick) error
+}
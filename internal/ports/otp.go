@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// OTPRepository persists one-time passwords.
+type OTPRepository interface {
+	// SaveOTP stores a new OTP unless the number has another OTP created
+	// after since, sent or not, and reports whether it was stored. Calls for
+	// the same number are serialized, so two cannot both pass the check.
+	SaveOTP(ctx context.Context, otp domain.OTP, since time.Time) (bool, error)
+
+	// AttachOTPMessage records the message that carried the OTP's code and
+	// marks the number's other pending OTPs replaced.
+	AttachOTPMessage(ctx context.Context, id, messageID uuid.UUID) error
+
+	// GetOTP returns an OTP; a missing one is ErrOTPNotFound.
+	GetOTP(ctx context.Context, id uuid.UUID) (domain.OTP, error)
+
+	// RecordOTPAttempt counts one verification attempt, provided the OTP is
+	// pending, unexpired at now and has attempts left. It reports whether
+	// the attempt was counted and returns the attempts made so far.
+	RecordOTPAttempt(ctx context.Context, id uuid.UUID, now time.Time) (int, bool, error)
+
+	// FinishOTP moves a pending OTP to a final status and reports whether it
+	// was still pending.
+	FinishOTP(ctx context.Context, id uuid.UUID, status domain.OTPStatus, at time.Time) (bool, error)
+
+	// FailedOTPAttempts counts the wrong codes entered for phone on OTPs
+	// created after since.
+	FailedOTPAttempts(ctx context.Context, phone string, since time.Time) (int, error)
+}
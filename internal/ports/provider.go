@@ -2,25 +2,55 @@ package ports
 
 import (
 	"context"
+	"net/url"
+	"time"
 
 	"golang-sms-broadcast/internal/domain"
-
-	"github.com/google/uuid"
 )
 
 // SendResult is the response from the SMS provider after submitting a message.
 type SendResult struct {
+	Provider   string // Name the provider's receipts arrive under (the /dlr/:provider segment)
 	ProviderID string // External message ID assigned by the provider
 }
 
 // SMSProvider abstracts the external SMS gateway.
 type SMSProvider interface {
 	// Send submits an SMS to the provider and returns the provider's message ID.
+	// msg.Sender is the originator to send from; empty means the provider's
+	// default for the destination.
 	Send(ctx context.Context, msg domain.Message) (SendResult, error)
 }
 
 // DLRPayload is the normalised delivery receipt from the provider's webhook.
 type DLRPayload struct {
-	ProviderID uuid.UUID
-	Status     domain.Status
+	Provider         string        // Name of the provider that sent the receipt
+	ProviderID       string        // External message ID assigned by the provider
+	Status           domain.Status // Status mapped onto the domain set
+	ErrorCode        string        // Carrier error code, empty when none was reported
+	ErrorDescription string        // Human-readable description of ErrorCode, if known
+	DoneAt           time.Time     // When the carrier reached the final state; zero if not reported
+}
+
+// DLRParser decodes a provider-specific delivery receipt callback.
+type DLRParser interface {
+	// Parse converts the raw webhook body into a normalised DLRPayload.
+	Parse(contentType string, body []byte) (DLRPayload, error)
+}
+
+// InboundMessage is a normalised mobile-originated (MO) message from a provider.
+type InboundMessage struct {
+	Provider   string    // Name of the provider that delivered the message
+	ProviderID string    // Provider's ID for the message; empty if not reported
+	From       string    // Subscriber's phone number
+	To         string    // Our number, short code or sender ID it was sent to
+	Body       string    // Message text
+	ReceivedAt time.Time // When the provider received it; zero if not reported
+}
+
+// InboundParser decodes a provider-specific inbound message webhook.
+type InboundParser interface {
+	// ParseInbound converts the raw webhook request into a normalised
+	// InboundMessage. Some gateways pass addresses as query parameters.
+	ParseInbound(contentType string, query url.Values, body []byte) (InboundMessage, error)
 }
@@ -18,3 +18,32 @@ type MessageConsumer interface {
 	// Blocks until ctx is cancelled or a fatal error occurs.
 	Consume(ctx context.Context, handler func(ctx context.Context, msg domain.Message) error) error
 }
+
+// DLRPublisher enqueues delivery receipts for asynchronous processing.
+type DLRPublisher interface {
+	// PublishDLR durably enqueues a single receipt.
+	PublishDLR(ctx context.Context, dlr DLRPayload) error
+}
+
+// DLRBatchHandler applies a batch of receipts.
+type DLRBatchHandler func(ctx context.Context, batch []DLRPayload) error
+
+// DLRConsumer consumes queued delivery receipts in batches.
+type DLRConsumer interface {
+	// ConsumeBatches passes receipts to handler in batches. If handler returns an
+	// error the whole batch is requeued. Blocks until ctx is cancelled or a fatal error occurs.
+	ConsumeBatches(ctx context.Context, handler DLRBatchHandler) error
+}
+
+// InboundPublisher enqueues inbound messages for asynchronous processing.
+type InboundPublisher interface {
+	// PublishInbound durably enqueues a single inbound message.
+	PublishInbound(ctx context.Context, msg InboundMessage) error
+}
+
+// InboundConsumer consumes queued inbound messages.
+type InboundConsumer interface {
+	// ConsumeInbound passes each inbound message to handler and requeues it
+	// if handler returns an error. Blocks until ctx is cancelled or a fatal error occurs.
+	ConsumeInbound(ctx context.Context, handler func(ctx context.Context, msg InboundMessage) error) error
+}
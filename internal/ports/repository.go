@@ -2,32 +2,121 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"golang-sms-broadcast/internal/domain"
 
 	"github.com/google/uuid"
 )
 
+// UnitOfWork runs several repository operations as one atomic unit.
+type UnitOfWork interface {
+	// WithinTx calls fn with a repository bound to a single transaction.
+	// The transaction commits if fn returns nil and rolls back otherwise.
+	WithinTx(ctx context.Context, fn func(repo MessageRepository) error) error
+}
+
 // MessageRepository defines persistence operations for messages and broadcasts.
 type MessageRepository interface {
+	UnitOfWork
+
 	// SaveBroadcast persists a new Broadcast.
 	SaveBroadcast(ctx context.Context, b domain.Broadcast) error
 
+	// SaveBroadcastCounts stores the creation counts of b on the saved
+	// broadcast with the same ID.
+	SaveBroadcastCounts(ctx context.Context, b domain.Broadcast) error
+
 	// GetBroadcast retrieves a broadcast by ID with all its messages.
 	GetBroadcast(ctx context.Context, id uuid.UUID) (*domain.Broadcast, error)
 
+	// FindBroadcast retrieves a broadcast without its messages; a missing
+	// one is ErrBroadcastNotFound.
+	FindBroadcast(ctx context.Context, id uuid.UUID) (*domain.Broadcast, error)
+
+	// BroadcastStats counts a broadcast's messages by status and the clicks
+	// on its short links, per A/B test variant. Messages without a variant
+	// are counted under "".
+	BroadcastStats(ctx context.Context, id uuid.UUID) (map[string]domain.BroadcastStats, error)
+
+	// EnsureBroadcast persists a Broadcast unless one with the same ID exists,
+	// for system broadcasts that several writers may create concurrently.
+	EnsureBroadcast(ctx context.Context, b domain.Broadcast) error
+
+	// GetMessage retrieves a single message; a missing one is ErrMessageNotFound.
+	GetMessage(ctx context.Context, id uuid.UUID) (*domain.Message, error)
+
 	// SaveMessages persists a batch of Messages in a single transaction.
 	SaveMessages(ctx context.Context, msgs []domain.Message) error
 
-	// GetPendingMessages returns up to limit messages with StatusPending.
+	// SaveShortLinks persists the short links rewritten into message bodies.
+	SaveShortLinks(ctx context.Context, links []domain.ShortLink) error
+
+	// GetPendingMessages returns up to limit messages with StatusPending
+	// whose NotBefore has passed, oldest first.
 	GetPendingMessages(ctx context.Context, limit int) ([]domain.Message, error)
 
+	// DeferMessages moves NotBefore of still pending messages forward to until.
+	DeferMessages(ctx context.Context, ids []uuid.UUID, until time.Time) error
+
+	// DeliveryWindows returns the delivery windows of the given broadcasts,
+	// omitting broadcasts without one.
+	DeliveryWindows(ctx context.Context, broadcastIDs []uuid.UUID) (map[uuid.UUID]domain.DeliveryWindow, error)
+
 	// UpdateMessageStatus transitions a message to the given status.
 	UpdateMessageStatus(ctx context.Context, id uuid.UUID, status domain.Status) error
 
-	// UpdateMessageStatusByProviderID transitions a message by the provider's external ID.
-	UpdateMessageStatusByProviderID(ctx context.Context, providerID string, status domain.Status) error
+	// SuppressMessage marks a queued or pending message suppressed with the
+	// reason it was not sent, and reports whether it was still queued or
+	// pending; a message already sent or suppressed is left as is.
+	SuppressMessage(ctx context.Context, id uuid.UUID, errorCode, errorDescription string) (bool, error)
+
+	// CountMarketingMessages counts, per number, the messages of marketing
+	// broadcasts created after since that are in one of the given statuses.
+	CountMarketingMessages(ctx context.Context, phones []string, since time.Time, statuses []domain.Status) (map[string]int, error)
+
+	// RecentContentHashes returns which of the given content hashes belong to
+	// messages created after since that are in one of the given statuses.
+	RecentContentHashes(ctx context.Context, hashes []string, since time.Time, statuses []domain.Status) (map[string]bool, error)
+
+	// BroadcastCategory returns the category of a broadcast.
+	BroadcastCategory(ctx context.Context, id uuid.UUID) (domain.Category, error)
+
+	// ApplyReceipts records a batch of delivery receipts, matching messages by
+	// Receipt.Provider and Receipt.ProviderID, and returns the receipts that
+	// matched no message.
+	ApplyReceipts(ctx context.Context, receipts []domain.Receipt) (unmatched []domain.Receipt, err error)
+
+	// ParkReceipts stores receipts that matched no message in pending_dlrs until expiresAt.
+	ParkReceipts(ctx context.Context, receipts []domain.Receipt, expiresAt time.Time) error
+
+	// ReplayPendingReceipts applies and removes parked receipts whose provider
+	// reference is now attached to a message. It returns the number of messages updated.
+	ReplayPendingReceipts(ctx context.Context, refs []domain.ProviderRef) (int64, error)
+
+	// PurgeExpiredReceipts deletes parked receipts that expired before now.
+	PurgeExpiredReceipts(ctx context.Context, now time.Time) (int64, error)
+
+	// EnqueueStatusCallbacks queues a callback event for the current status of
+	// each message whose broadcast has a callback URL, plus a broadcast.completed
+	// event once every message of the broadcast is final. Each status is queued once.
+	EnqueueStatusCallbacks(ctx context.Context, messageIDs []uuid.UUID) error
+
+	// EnqueueStatusCallbacksByProviderRef is EnqueueStatusCallbacks keyed by provider reference.
+	EnqueueStatusCallbacksByProviderRef(ctx context.Context, refs []domain.ProviderRef) error
+
+	// ClaimIdempotencyKey stores key unless an unexpired key with the same
+	// name exists. It returns nil when the key was claimed, or the stored key.
+	ClaimIdempotencyKey(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyKey, error)
+
+	// SaveIdempotencyResult stores the counts of key on the claimed key with
+	// the same name.
+	SaveIdempotencyResult(ctx context.Context, key domain.IdempotencyKey) error
+
+	// PurgeExpiredIdempotencyKeys deletes keys that expired before now.
+	PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
 
-	// SetProviderID stores the external SMS provider ID on a message after submission.
-	SetProviderID(ctx context.Context, id uuid.UUID, providerID string) error
+	// SetProviderID stores the provider name and external SMS provider ID on a
+	// message after submission.
+	SetProviderID(ctx context.Context, id uuid.UUID, ref domain.ProviderRef) error
 }
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"golang-sms-broadcast/internal/domain"
+)
+
+// SenderRepository persists the registry of sender IDs.
+type SenderRepository interface {
+	// CreateSender registers a sender; an existing one is ErrSenderExists.
+	CreateSender(ctx context.Context, sender domain.SenderID) error
+
+	// UpdateSender replaces the type, country codes and description of a
+	// registered sender; a missing one is ErrSenderNotFound.
+	UpdateSender(ctx context.Context, sender domain.SenderID) error
+
+	// DeleteSender removes a sender; a missing one is ErrSenderNotFound.
+	DeleteSender(ctx context.Context, sender string) error
+
+	// GetSender returns one sender; a missing one is ErrSenderNotFound.
+	GetSender(ctx context.Context, sender string) (domain.SenderID, error)
+
+	// ListSenders returns all registered senders ordered by name.
+	ListSenders(ctx context.Context) ([]domain.SenderID, error)
+}
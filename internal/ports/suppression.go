@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"golang-sms-broadcast/internal/domain"
+)
+
+// SuppressionFilter selects a page of suppression entries.
+type SuppressionFilter struct {
+	Sender *string // Only entries for this sender ("" for global); nil for all
+	Phone  string  // Only entries for this number; empty for all
+	Limit  int
+	Offset int
+}
+
+// SuppressionRepository persists the opt-out list.
+type SuppressionRepository interface {
+	// AddSuppressions stores entries, ignoring ones already on the list,
+	// and returns how many were new.
+	AddSuppressions(ctx context.Context, entries []domain.Suppression) (int64, error)
+
+	// RemoveSuppression deletes one entry; a missing entry is ErrSuppressionNotFound.
+	RemoveSuppression(ctx context.Context, phone, sender string) error
+
+	// ListSuppressions returns a page of entries, newest first.
+	ListSuppressions(ctx context.Context, filter SuppressionFilter) ([]domain.Suppression, error)
+
+	// SuppressedPhones returns which of phones may not be messaged by sender,
+	// through either a global entry or one for that sender.
+	SuppressedPhones(ctx context.Context, sender string, phones []string) (map[string]bool, error)
+
+	// SuppressedSince reports whether phone was suppressed for sender after since.
+	SuppressedSince(ctx context.Context, sender, phone string, since time.Time) (bool, error)
+}
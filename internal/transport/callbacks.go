@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CallbackHandler exposes the callback delivery log and manual replay.
+type CallbackHandler struct {
+	callbacks *app.CallbackService
+	log       *slog.Logger
+}
+
+// NewCallbackHandler wires up a CallbackHandler with its dependencies.
+func NewCallbackHandler(callbacks *app.CallbackService, log *slog.Logger) *CallbackHandler {
+	return &CallbackHandler{callbacks: callbacks, log: log}
+}
+
+// Register mounts the callback admin routes onto the given router.
+func (h *CallbackHandler) Register(router fiber.Router) {
+	router.Get("/callbacks", h.ListCallbacks)
+	router.Get("/callbacks/:id", h.GetCallback)
+	router.Post("/callbacks/:id/replay", h.ReplayCallback)
+}
+
+type callbackResponse struct {
+	ID            string                  `json:"id"`
+	BroadcastID   string                  `json:"broadcast_id"`
+	MessageID     *string                 `json:"message_id,omitempty"`
+	Event         string                  `json:"event"`
+	URL           string                  `json:"url"`
+	Status        domain.CallbackStatus   `json:"status"`
+	Attempts      int                     `json:"attempts"`
+	NextAttemptAt string                  `json:"next_attempt_at"`
+	LastError     string                  `json:"last_error,omitempty"`
+	Log           []callbackAttemptRecord `json:"log,omitempty"`
+}
+
+type callbackAttemptRecord struct {
+	Attempt     int    `json:"attempt"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DurationMS  int64  `json:"duration_ms"`
+	AttemptedAt string `json:"attempted_at"`
+}
+
+// ListCallbacks returns the recent callback events of a broadcast.
+//
+// GET /callbacks?broadcast_id=...&limit=100 (limit 1-500)
+func (h *CallbackHandler) ListCallbacks(c *fiber.Ctx) error {
+	broadcastID, err := uuid.Parse(c.Query("broadcast_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "broadcast_id is required"})
+	}
+
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	events, err := h.callbacks.ListCallbacks(c.Context(), broadcastID, limit)
+	if err != nil {
+		h.log.Error("list callbacks", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	resp := make([]callbackResponse, 0, len(events))
+	for _, event := range events {
+		resp = append(resp, toCallbackResponse(event, nil))
+	}
+	return c.JSON(resp)
+}
+
+// GetCallback returns a callback event with its delivery log.
+//
+// GET /callbacks/:id
+func (h *CallbackHandler) GetCallback(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid callback id"})
+	}
+
+	event, attempts, err := h.callbacks.GetCallback(c.Context(), id)
+	if err != nil {
+		return h.callbackError(c, err)
+	}
+
+	return c.JSON(toCallbackResponse(*event, attempts))
+}
+
+// ReplayCallback schedules a callback event for immediate redelivery.
+//
+// POST /callbacks/:id/replay
+func (h *CallbackHandler) ReplayCallback(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid callback id"})
+	}
+
+	if err := h.callbacks.ReplayCallback(c.Context(), id); err != nil {
+		return h.callbackError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"status": "queued"})
+}
+
+func (h *CallbackHandler) callbackError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrCallbackNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "callback not found"})
+	}
+	h.log.Error("callback admin", "err", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
+
+func toCallbackResponse(event domain.CallbackEvent, attempts []domain.CallbackAttempt) callbackResponse {
+	resp := callbackResponse{
+		ID:            event.ID.String(),
+		BroadcastID:   event.BroadcastID.String(),
+		Event:         event.Event,
+		URL:           event.URL,
+		Status:        event.Status,
+		Attempts:      event.Attempts,
+		NextAttemptAt: event.NextAttemptAt.UTC().Format(time.RFC3339),
+		LastError:     event.LastError,
+	}
+	if event.MessageID != nil {
+		id := event.MessageID.String()
+		resp.MessageID = &id
+	}
+	for _, a := range attempts {
+		resp.Log = append(resp.Log, callbackAttemptRecord{
+			Attempt:     a.Attempt,
+			StatusCode:  a.StatusCode,
+			Error:       a.Error,
+			DurationMS:  a.DurationMS,
+			AttemptedAt: a.AttemptedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return resp
+}
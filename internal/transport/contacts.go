@@ -0,0 +1,448 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ContactHandler holds the HTTP handlers for contacts and contact lists.
+type ContactHandler struct {
+	contacts *app.ContactService
+	log      *slog.Logger
+}
+
+// NewContactHandler wires up a ContactHandler with its dependencies.
+func NewContactHandler(contacts *app.ContactService, log *slog.Logger) *ContactHandler {
+	return &ContactHandler{contacts: contacts, log: log}
+}
+
+// Register mounts the contact and list routes onto the given router.
+func (h *ContactHandler) Register(router fiber.Router) {
+	router.Post("/contacts", h.CreateContact)
+	router.Post("/contacts/import", h.ImportContacts)
+	router.Get("/contacts", h.ListContacts)
+	router.Get("/contacts/:id", h.GetContact)
+	router.Put("/contacts/:id", h.UpdateContact)
+	router.Delete("/contacts/:id", h.DeleteContact)
+
+	router.Post("/lists", h.CreateList)
+	router.Get("/lists", h.ListLists)
+	router.Get("/lists/:id", h.GetList)
+	router.Put("/lists/:id", h.UpdateList)
+	router.Delete("/lists/:id", h.DeleteList)
+	router.Post("/lists/:id/contacts", h.AddListMembers)
+	router.Delete("/lists/:id/contacts", h.RemoveListMembers)
+
+	router.Post("/segments/preview", h.PreviewSegment)
+}
+
+// ── Contacts ──────────────────────────────────────────────────────────────────
+
+type contactRequest struct {
+	Phone      string            `json:"phone"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+type contactResponse struct {
+	ID         string            `json:"id"`
+	Phone      string            `json:"phone"`
+	Attributes map[string]string `json:"attributes"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// CreateContact adds a contact to the address book.
+//
+// POST /contacts
+// Body: { "phone": "+66812345678", "attributes": { "first_name": "Ann" } }
+func (h *ContactHandler) CreateContact(c *fiber.Ctx) error {
+	var req contactRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	contact, err := h.contacts.CreateContact(c.Context(), req.Phone, req.Attributes)
+	if err != nil {
+		return h.contactError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(toContactResponse(contact))
+}
+
+// ListContacts returns a page of contacts, optionally of one list.
+//
+// GET /contacts?list_id=...&limit=100&offset=0
+func (h *ContactHandler) ListContacts(c *fiber.Ctx) error {
+	filter := ports.ContactFilter{
+		Limit:  c.QueryInt("limit", 100),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if filter.Limit <= 0 || filter.Limit > 1000 {
+		filter.Limit = 100
+	}
+	if raw := c.Query("list_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid list_id"})
+		}
+		filter.ListID = &id
+	}
+
+	contacts, err := h.contacts.ListContacts(c.Context(), filter)
+	if err != nil {
+		return h.contactError(c, err)
+	}
+
+	resp := make([]contactResponse, 0, len(contacts))
+	for _, contact := range contacts {
+		resp = append(resp, toContactResponse(contact))
+	}
+	return c.JSON(resp)
+}
+
+// GetContact returns one contact.
+//
+// GET /contacts/:id
+func (h *ContactHandler) GetContact(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid contact id"})
+	}
+
+	contact, err := h.contacts.GetContact(c.Context(), id)
+	if err != nil {
+		return h.contactError(c, err)
+	}
+
+	return c.JSON(toContactResponse(*contact))
+}
+
+// UpdateContact replaces a contact's phone and attributes.
+//
+// PUT /contacts/:id
+func (h *ContactHandler) UpdateContact(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid contact id"})
+	}
+
+	var req contactRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	contact, err := h.contacts.UpdateContact(c.Context(), id, req.Phone, req.Attributes)
+	if err != nil {
+		return h.contactError(c, err)
+	}
+
+	return c.JSON(toContactResponse(contact))
+}
+
+// DeleteContact removes a contact from the address book and all lists.
+//
+// DELETE /contacts/:id
+func (h *ContactHandler) DeleteContact(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid contact id"})
+	}
+
+	if err := h.contacts.DeleteContact(c.Context(), id); err != nil {
+		return h.contactError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+type importContactsResponse struct {
+	Imported     int               `json:"imported"`
+	Rejected     int               `json:"rejected"`
+	RejectedRows []app.RejectedRow `json:"rejected_rows"`
+}
+
+// ImportContacts bulk-loads contacts from a CSV file. Every column other
+// than the phone column becomes a contact attribute.
+//
+// POST /contacts/import (multipart/form-data)
+// Fields: file, phone_column (optional, default "phone"), list_id (optional)
+func (h *ContactHandler) ImportContacts(c *fiber.Ctx) error {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
+	}
+
+	req := app.ImportContactsRequest{PhoneColumn: c.FormValue("phone_column")}
+	if raw := c.FormValue("list_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid list_id"})
+		}
+		req.ListID = &id
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		h.log.Error("open upload", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+	defer file.Close()
+	req.CSV = file
+
+	result, err := h.contacts.ImportContacts(c.Context(), req)
+	if err != nil {
+		return h.contactError(c, err)
+	}
+
+	resp := importContactsResponse{
+		Imported:     result.Imported,
+		Rejected:     result.Rejected,
+		RejectedRows: result.RejectedRows,
+	}
+	if resp.RejectedRows == nil {
+		resp.RejectedRows = []app.RejectedRow{}
+	}
+	return c.JSON(resp)
+}
+
+// ── Lists ─────────────────────────────────────────────────────────────────────
+
+type listRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type listResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Members     int64     `json:"members"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type listMembersRequest struct {
+	ContactIDs []uuid.UUID `json:"contact_ids"`
+}
+
+// CreateList creates an empty contact list.
+//
+// POST /lists
+// Body: { "name": "...", "description": "..." }
+func (h *ContactHandler) CreateList(c *fiber.Ctx) error {
+	var req listRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name is required"})
+	}
+
+	list, err := h.contacts.CreateList(c.Context(), req.Name, req.Description)
+	if err != nil {
+		return h.contactError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(toListResponse(list))
+}
+
+// ListLists returns all contact lists with their member counts.
+//
+// GET /lists
+func (h *ContactHandler) ListLists(c *fiber.Ctx) error {
+	lists, err := h.contacts.ListLists(c.Context())
+	if err != nil {
+		return h.contactError(c, err)
+	}
+
+	resp := make([]listResponse, 0, len(lists))
+	for _, list := range lists {
+		resp = append(resp, toListResponse(list))
+	}
+	return c.JSON(resp)
+}
+
+// GetList returns one contact list with its member count.
+//
+// GET /lists/:id
+func (h *ContactHandler) GetList(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid list id"})
+	}
+
+	list, err := h.contacts.GetList(c.Context(), id)
+	if err != nil {
+		return h.contactError(c, err)
+	}
+
+	return c.JSON(toListResponse(*list))
+}
+
+// UpdateList renames a contact list.
+//
+// PUT /lists/:id
+func (h *ContactHandler) UpdateList(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid list id"})
+	}
+
+	var req listRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name is required"})
+	}
+
+	list, err := h.contacts.UpdateList(c.Context(), id, req.Name, req.Description)
+	if err != nil {
+		return h.contactError(c, err)
+	}
+
+	return c.JSON(toListResponse(*list))
+}
+
+// DeleteList removes a contact list; its contacts are kept.
+//
+// DELETE /lists/:id
+func (h *ContactHandler) DeleteList(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid list id"})
+	}
+
+	if err := h.contacts.DeleteList(c.Context(), id); err != nil {
+		return h.contactError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// AddListMembers adds existing contacts to a list.
+//
+// POST /lists/:id/contacts
+// Body: { "contact_ids": ["...", ...] }
+func (h *ContactHandler) AddListMembers(c *fiber.Ctx) error {
+	return h.changeMembers(c, h.contacts.AddListMembers)
+}
+
+// RemoveListMembers removes contacts from a list.
+//
+// DELETE /lists/:id/contacts
+// Body: { "contact_ids": ["...", ...] }
+func (h *ContactHandler) RemoveListMembers(c *fiber.Ctx) error {
+	return h.changeMembers(c, h.contacts.RemoveListMembers)
+}
+
+func (h *ContactHandler) changeMembers(c *fiber.Ctx, change func(ctx context.Context, listID uuid.UUID, contactIDs []uuid.UUID) error) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid list id"})
+	}
+
+	var req listMembersRequest
+	if err := c.BodyParser(&req); err != nil || len(req.ContactIDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "contact_ids are required"})
+	}
+
+	if err := change(c.Context(), id, req.ContactIDs); err != nil {
+		return h.contactError(c, err)
+	}
+
+	list, err := h.contacts.GetList(c.Context(), id)
+	if err != nil {
+		return h.contactError(c, err)
+	}
+	return c.JSON(toListResponse(*list))
+}
+
+// ── Segments ──────────────────────────────────────────────────────────────────
+
+type segmentPreviewRequest struct {
+	Segment string      `json:"segment"`
+	ListIDs []uuid.UUID `json:"list_ids"`
+}
+
+type segmentPreviewResponse struct {
+	Count  int64             `json:"count"`
+	Sample []contactResponse `json:"sample"`
+}
+
+// PreviewSegment returns how many contacts a segment matches, and a sample,
+// before a broadcast is created for it.
+//
+// POST /segments/preview
+// Body: { "segment": "city = \"Bangkok\" AND tier IN (\"gold\", \"platinum\")", "list_ids": ["..."] }
+func (h *ContactHandler) PreviewSegment(c *fiber.Ctx) error {
+	var req segmentPreviewRequest
+	if err := c.BodyParser(&req); err != nil || req.Segment == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "segment is required"})
+	}
+
+	preview, err := h.contacts.PreviewSegment(c.Context(), req.Segment, req.ListIDs)
+	if err != nil {
+		if errors.Is(err, domain.ErrListNotFound) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unknown list_ids"})
+		}
+		return h.contactError(c, err)
+	}
+
+	resp := segmentPreviewResponse{Count: preview.Count, Sample: make([]contactResponse, 0, len(preview.Sample))}
+	for _, contact := range preview.Sample {
+		resp.Sample = append(resp.Sample, toContactResponse(contact))
+	}
+	return c.JSON(resp)
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────────────
+
+func (h *ContactHandler) contactError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrContactNotFound), errors.Is(err, domain.ErrListNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrContactExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidPhone), errors.Is(err, domain.ErrInvalidUpload),
+		errors.Is(err, domain.ErrInvalidSegment):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	h.log.Error("contacts", "err", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
+
+func toContactResponse(contact domain.Contact) contactResponse {
+	attributes := contact.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	return contactResponse{
+		ID:         contact.ID.String(),
+		Phone:      contact.Phone,
+		Attributes: attributes,
+		CreatedAt:  contact.CreatedAt,
+		UpdatedAt:  contact.UpdatedAt,
+	}
+}
+
+func toListResponse(list domain.ContactList) listResponse {
+	return listResponse{
+		ID:          list.ID.String(),
+		Name:        list.Name,
+		Description: list.Description,
+		Members:     list.Members,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+}
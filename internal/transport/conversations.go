@@ -0,0 +1,153 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConversationHandler holds the HTTP handlers for two-way conversation threads.
+type ConversationHandler struct {
+	svc     *app.BroadcastService
+	inbound *app.InboundService
+	log     *slog.Logger
+}
+
+// NewConversationHandler wires up a ConversationHandler with its dependencies.
+func NewConversationHandler(svc *app.BroadcastService, inbound *app.InboundService, log *slog.Logger) *ConversationHandler {
+	return &ConversationHandler{svc: svc, inbound: inbound, log: log}
+}
+
+// Register mounts the conversation routes onto the given router.
+func (h *ConversationHandler) Register(router fiber.Router) {
+	router.Get("/conversations/:phone", h.GetConversation)
+	router.Post("/conversations/:phone/replies", h.Reply)
+}
+
+type conversationEntryResponse struct {
+	ID          string    `json:"id"`
+	Direction   string    `json:"direction"`
+	Body        string    `json:"body"`
+	Status      string    `json:"status,omitempty"`
+	BroadcastID string    `json:"broadcast_id,omitempty"`
+	InReplyTo   string    `json:"in_reply_to,omitempty"`
+	Keyword     string    `json:"keyword,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+type conversationResponse struct {
+	Phone      string                      `json:"phone"`
+	Messages   []conversationEntryResponse `json:"messages"`
+	NextBefore *time.Time                  `json:"next_before,omitempty"`
+}
+
+// GetConversation returns the thread with a phone number, oldest first. A full
+// page carries next_before, which fetches the page of older messages.
+//
+// GET /conversations/:phone?before=2026-01-02T15:04:05Z&limit=50
+func (h *ConversationHandler) GetConversation(c *fiber.Ctx) error {
+	phone, err := url.PathUnescape(c.Params("phone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid phone"})
+	}
+
+	var before time.Time
+	if raw := c.Query("before"); raw != "" {
+		if before, err = time.Parse(time.RFC3339Nano, raw); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "before must be an RFC 3339 timestamp"})
+		}
+	}
+
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	entries, err := h.inbound.Conversation(c.Context(), phone, before, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPhone) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.log.Error("get conversation", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	resp := conversationResponse{Messages: make([]conversationEntryResponse, 0, len(entries))}
+	resp.Phone, _ = domain.NormalizePhone(phone)
+	for _, e := range entries {
+		entry := conversationEntryResponse{
+			ID:        e.ID.String(),
+			Direction: string(e.Direction),
+			Body:      e.Body,
+			Status:    string(e.Status),
+			Keyword:   e.Keyword,
+			At:        e.At,
+		}
+		if e.BroadcastID != nil {
+			entry.BroadcastID = e.BroadcastID.String()
+		}
+		if e.InReplyTo != nil {
+			entry.InReplyTo = e.InReplyTo.String()
+		}
+		resp.Messages = append(resp.Messages, entry)
+	}
+	if len(entries) == limit {
+		resp.NextBefore = &entries[0].At
+	}
+
+	return c.JSON(resp)
+}
+
+type replyRequest struct {
+	Body string `json:"body"`
+}
+
+type replyResponse struct {
+	MessageID   string `json:"message_id"`
+	BroadcastID string `json:"broadcast_id"`
+	Status      string `json:"status"`
+}
+
+// Reply queues a message to the phone number in its conversation thread.
+// Numbers on the global suppression list are refused with 409.
+//
+// POST /conversations/:phone/replies
+// Body: { "body": "..." }
+func (h *ConversationHandler) Reply(c *fiber.Ctx) error {
+	phone, err := url.PathUnescape(c.Params("phone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid phone"})
+	}
+
+	var req replyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Body == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "body is required"})
+	}
+
+	msg, err := h.svc.ReplyInThread(c.Context(), phone, req.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidPhone):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrRecipientSuppressed):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.log.Error("reply in thread", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(replyResponse{
+		MessageID:   msg.ID.String(),
+		BroadcastID: msg.BroadcastID.String(),
+		Status:      string(msg.Status),
+	})
+}
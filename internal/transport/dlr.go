@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultDLRProvider is the parser used by the bare /dlr route.
+const defaultDLRProvider = "mock"
+
+// DLRHandler receives delivery receipt webhooks from SMS providers.
+type DLRHandler struct {
+	receipts *app.ReceiptService
+	parsers  map[string]ports.DLRParser
+	inbound  *InboundHandler
+	log      *slog.Logger
+}
+
+// NewDLRHandler wires up a DLRHandler with its dependencies.
+// parsers maps a provider name (the :provider route segment) to its DLR format.
+// Callbacks a parser reports as domain.ErrNotReceipt, such as SMPP deliver_sm
+// PDUs carrying a subscriber's message, are passed on to inbound if set.
+func NewDLRHandler(receipts *app.ReceiptService, parsers map[string]ports.DLRParser, inbound *InboundHandler, log *slog.Logger) *DLRHandler {
+	return &DLRHandler{receipts: receipts, parsers: parsers, inbound: inbound, log: log}
+}
+
+// Register mounts the delivery receipt routes, running mw (e.g. signature
+// verification) before the handler.
+func (h *DLRHandler) Register(router fiber.Router, mw ...fiber.Handler) {
+	handlers := append(mw, h.HandleDLR)
+	router.Post("/dlr", handlers...)
+	router.Post("/dlr/:provider", handlers...)
+}
+
+// HandleDLR parses a delivery receipt and enqueues it for the dlr-processor.
+// The body format depends on the provider segment of the route, see adapters/dlr.
+//
+// POST /dlr            (generic JSON: { "provider_id": "...", "status": "delivered"|"failed" })
+// POST /dlr/:provider  (e.g. /dlr/twilio, /dlr/smpp)
+func (h *DLRHandler) HandleDLR(c *fiber.Ctx) error {
+	provider := c.Params("provider", defaultDLRProvider)
+
+	parser, ok := h.parsers[provider]
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "unknown provider"})
+	}
+
+	dlr, err := parser.Parse(string(c.Request().Header.ContentType()), c.Body())
+	if errors.Is(err, domain.ErrNotReceipt) && h.inbound != nil {
+		return h.inbound.HandleInbound(c)
+	}
+	if err != nil {
+		h.log.Warn("parse dlr", "provider", provider, "err", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	dlr.Provider = provider
+
+	if err := h.receipts.AcceptDLR(c.Context(), dlr); err != nil {
+		if errors.Is(err, domain.ErrUnknownStatus) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.log.Error("accept dlr", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
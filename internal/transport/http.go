@@ -1,11 +1,22 @@
 package transport
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"mime/multipart"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"golang-sms-broadcast/internal/app"
 	"golang-sms-broadcast/internal/domain"
-	"golang-sms-broadcast/internal/ports"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -13,19 +24,22 @@ import (
 
 // Handler holds all HTTP handlers for the SMS broadcast service.
 type Handler struct {
-	svc *app.BroadcastService
-	log *slog.Logger
+	svc  *app.BroadcastService
+	jobs *app.JobService
+	log  *slog.Logger
 }
 
 // NewHandler wires up a Handler with its dependencies.
-func NewHandler(svc *app.BroadcastService, log *slog.Logger) *Handler {
-	return &Handler{svc: svc, log: log}
+func NewHandler(svc *app.BroadcastService, jobs *app.JobService, log *slog.Logger) *Handler {
+	return &Handler{svc: svc, jobs: jobs, log: log}
 }
 
-// Register mounts all routes onto the given Fiber app.
+// Register mounts the broadcast API routes onto the given Fiber app.
 func (h *Handler) Register(router fiber.Router) {
 	router.Post("/broadcasts", h.CreateBroadcast)
-	router.Post("/dlr", h.HandleDLR)
+	router.Post("/broadcasts/upload", h.UploadBroadcast)
+	router.Get("/broadcasts/:id", h.GetBroadcast)
+	router.Get("/jobs/:id", h.GetJob)
 }
 
 // ── Broadcast API ─────────────────────────────────────────────────────────────
@@ -34,82 +48,578 @@ type createBroadcastRequest struct {
 	Name       string   `json:"name"`
 	Body       string   `json:"body"`
 	Recipients []string `json:"recipients"`
+
+	CallbackURL string      `json:"callback_url"`
+	Category    string      `json:"category"`
+	Sender      string      `json:"sender"`
+	ListIDs     []uuid.UUID `json:"list_ids"`
+	Segment     string      `json:"segment"`
+
+	DeliveryWindow *deliveryWindowRequest `json:"delivery_window"`
+	DedupHours     int                    `json:"dedup_hours"`
+	ShortenLinks   bool                   `json:"shorten_links"`
+	Variants       []domain.Variant       `json:"variants"`
+}
+
+// deliveryWindowRequest is a broadcast's allowed sending hours in the
+// recipient's local time, e.g. { "start": "08:00", "end": "20:00" }.
+type deliveryWindowRequest struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
 }
 
 type createBroadcastResponse struct {
 	BroadcastID string `json:"broadcast_id"`
 	Queued      int    `json:"queued"`
+	Rejected    int    `json:"rejected,omitempty"`
+	Suppressed  int    `json:"suppressed,omitempty"`
+	Capped      int    `json:"capped,omitempty"`
+	Duplicates  int    `json:"duplicates,omitempty"`
 }
 
+// Idempotency headers for safe retries of POST /broadcasts.
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLen = 255
+)
+
+// maxDedupHours bounds the duplicate lookback window to 30 days.
+const maxDedupHours = 30 * 24
+
 // CreateBroadcast accepts a broadcast request and saves it to the outbox.
+// With an Idempotency-Key header, a retried request returns the original
+// response and the same key with a different body is rejected with 409.
+// With ?async=true the broadcast is created by a background job instead.
 //
 // POST /broadcasts
-// Body: { "name": "...", "body": "...", "recipients": ["...", ...] }
+// Body: { "name": "...", "body": "...", "variants": [{ "name": "A", "body": "...", "percent": 50 }, ...], "recipients": ["...", ...], "list_ids": ["..."], "segment": "...", "callback_url": "https://...", "category": "marketing", "sender": "ACME", "delivery_window": { "start": "08:00", "end": "20:00" }, "dedup_hours": 24, "shorten_links": true }
 func (h *Handler) CreateBroadcast(c *fiber.Ctx) error {
 	var req createBroadcastRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
 	}
 
-	if req.Name == "" || req.Body == "" || len(req.Recipients) == 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name, body and recipients are required"})
+	if req.Name == "" || (req.Body == "" && len(req.Variants) == 0) || (len(req.Recipients) == 0 && len(req.ListIDs) == 0 && req.Segment == "") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name, body or variants, and recipients, list_ids or segment are required"})
+	}
+
+	if err := validateVariants(req.Body, req.Variants); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if req.CallbackURL != "" && !validCallbackURL(req.CallbackURL) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "callback_url must be an absolute http(s) URL"})
+	}
+
+	category, err := domain.ParseCategory(req.Category)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if req.DedupHours < 0 || req.DedupHours > maxDedupHours {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "dedup_hours must be between 0 and 720"})
 	}
 
-	broadcast, err := h.svc.CreateBroadcast(c.Context(), app.CreateBroadcastRequest{
-		Name:      req.Name,
-		Body:      req.Body,
-		Recipient: req.Recipients,
+	var window domain.DeliveryWindow
+	if req.DeliveryWindow != nil {
+		if window, err = domain.ParseDeliveryWindow(req.DeliveryWindow.Start, req.DeliveryWindow.End); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	idempotencyKey := c.Get(IdempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLen {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Idempotency-Key is too long"})
+	}
+
+	opts := domain.BroadcastOptions{
+		Name:         req.Name,
+		Body:         req.Body,
+		CallbackURL:  req.CallbackURL,
+		Category:     category,
+		Sender:       req.Sender,
+		Window:       window,
+		DedupHours:   req.DedupHours,
+		ShortenLinks: req.ShortenLinks,
+		Variants:     req.Variants,
+	}
+
+	if c.QueryBool("async") {
+		if idempotencyKey != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Idempotency-Key is not supported with async=true"})
+		}
+		job, err := h.jobs.SubmitBroadcast(c.Context(), app.CreateBroadcastRequest{
+			BroadcastOptions: opts,
+			Recipient:        req.Recipients,
+			ListIDs:          req.ListIDs,
+			Segment:          req.Segment,
+		})
+		return h.jobAccepted(c, job, err)
+	}
+
+	result, err := h.svc.CreateBroadcast(c.Context(), app.CreateBroadcastRequest{
+		BroadcastOptions: opts,
+		Recipient:        req.Recipients,
+		ListIDs:          req.ListIDs,
+		Segment:          req.Segment,
+		IdempotencyKey:   idempotencyKey,
 	})
 	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrIdempotencyConflict):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrListNotFound):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unknown list_ids"})
+		case errors.Is(err, domain.ErrInvalidSegment), errors.Is(err, domain.ErrSenderNotFound):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrNoRecipients):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "rejected": result.Rejected, "suppressed": result.Suppressed, "duplicates": result.Duplicates})
+		}
 		h.log.Error("create broadcast", "err", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
 	}
 
+	if result.Replayed {
+		c.Set(IdempotencyReplayedHeader, "true")
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(createBroadcastResponse{
-		BroadcastID: broadcast.ID.String(),
-		Queued:      len(req.Recipients),
+		BroadcastID: result.BroadcastID.String(),
+		Queued:      result.Queued,
+		Rejected:    result.Rejected,
+		Suppressed:  result.Suppressed,
+		Capped:      result.Capped,
+		Duplicates:  result.Duplicates,
 	})
 }
 
-// ── DLR Webhook ───────────────────────────────────────────────────────────────
+type uploadBroadcastResponse struct {
+	BroadcastID  string            `json:"broadcast_id,omitempty"`
+	Queued       int               `json:"queued"`
+	Rejected     int               `json:"rejected"`
+	Suppressed   int               `json:"suppressed"`
+	Capped       int               `json:"capped"`
+	Duplicates   int               `json:"duplicates"`
+	RejectedRows []app.RejectedRow `json:"rejected_rows"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// UploadBroadcast creates a broadcast from a CSV recipient list.
+// The file needs a header row with a phone column; other columns can be
+// used as {{column}} placeholders in the body. Invalid rows are skipped and
+// reported with their line numbers.
+//
+// With ?async=true the file is handed to a background job instead.
+//
+// POST /broadcasts/upload (multipart/form-data)
+// Fields: name, body or variants (JSON array), callback_url (optional), category (optional), sender (optional), phone_column (optional, default "phone"),
+// window_start and window_end (optional, "HH:MM"), dedup_hours (optional), shorten_links (optional, "true"), file
+func (h *Handler) UploadBroadcast(c *fiber.Ctx) error {
+	form, err := readUploadForm(c, "file")
+	switch {
+	case errors.Is(err, errInvalidForm):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		h.log.Error("read upload", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+	defer form.Close()
 
-type dlrRequest struct {
-	ProviderID string `json:"provider_id"`
-	Status     string `json:"status"`
+	name, body, callbackURL := form.value("name"), form.value("body"), form.value("callback_url")
+	var variants []domain.Variant
+	if raw := form.value("variants"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &variants); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "variants must be a JSON array"})
+		}
+	}
+
+	if name == "" || (body == "" && len(variants) == 0) || form.file == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name, body or variants, and file are required"})
+	}
+
+	if err := validateVariants(body, variants); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if callbackURL != "" && !validCallbackURL(callbackURL) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "callback_url must be an absolute http(s) URL"})
+	}
+
+	category, err := domain.ParseCategory(form.value("category"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	window, err := domain.ParseDeliveryWindow(form.value("window_start"), form.value("window_end"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	dedupHours := 0
+	if raw := form.value("dedup_hours"); raw != "" {
+		if dedupHours, err = strconv.Atoi(raw); err != nil || dedupHours < 0 || dedupHours > maxDedupHours {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "dedup_hours must be between 0 and 720"})
+		}
+	}
+
+	shortenLinks := false
+	if raw := form.value("shorten_links"); raw != "" {
+		if shortenLinks, err = strconv.ParseBool(raw); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "shorten_links must be true or false"})
+		}
+	}
+
+	req := app.UploadBroadcastRequest{
+		BroadcastOptions: domain.BroadcastOptions{
+			Name:         name,
+			Body:         body,
+			CallbackURL:  callbackURL,
+			Category:     category,
+			Sender:       form.value("sender"),
+			Window:       window,
+			DedupHours:   dedupHours,
+			ShortenLinks: shortenLinks,
+			Variants:     variants,
+		},
+		PhoneColumn: form.value("phone_column"),
+		CSV:         form.file,
+	}
+
+	if c.QueryBool("async") {
+		job, err := h.jobs.SubmitUpload(c.Context(), req)
+		return h.jobAccepted(c, job, err)
+	}
+
+	result, err := h.svc.CreateBroadcastFromCSV(c.Context(), req)
+
+	resp := uploadBroadcastResponse{
+		Queued:       result.Queued,
+		Rejected:     result.Rejected,
+		Suppressed:   result.Suppressed,
+		Capped:       result.Capped,
+		Duplicates:   result.Duplicates,
+		RejectedRows: result.RejectedRows,
+	}
+	if resp.RejectedRows == nil {
+		resp.RejectedRows = []app.RejectedRow{}
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidUpload), errors.Is(err, domain.ErrNoRecipients), errors.Is(err, domain.ErrSenderNotFound):
+		resp.Error = err.Error()
+		return c.Status(fiber.StatusBadRequest).JSON(resp)
+	case err != nil:
+		h.log.Error("upload broadcast", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	resp.BroadcastID = result.BroadcastID.String()
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+type broadcastResponse struct {
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Category     domain.Category        `json:"category"`
+	Sender       string                 `json:"sender,omitempty"`
+	ShortenLinks bool                   `json:"shorten_links"`
+	CreatedAt    time.Time              `json:"created_at"`
+	Stats        broadcastStatsResponse `json:"stats"`
+	Variants     []variantResponse      `json:"variants,omitempty"`
 }
 
-// HandleDLR receives delivery receipts from the SMS provider.
+type variantResponse struct {
+	Name    string                 `json:"name"`
+	Body    string                 `json:"body"`
+	Percent int                    `json:"percent"`
+	Stats   broadcastStatsResponse `json:"stats"`
+}
+
+type broadcastStatsResponse struct {
+	Total            int     `json:"total"`
+	Pending          int     `json:"pending"`
+	Queued           int     `json:"queued"`
+	Sent             int     `json:"sent"`
+	Delivered        int     `json:"delivered"`
+	Failed           int     `json:"failed"`
+	Suppressed       int     `json:"suppressed"`
+	DeliveryRate     float64 `json:"delivery_rate"`
+	Clicks           int     `json:"clicks"`
+	UniqueClicks     int     `json:"unique_clicks"`
+	ClickThroughRate float64 `json:"click_through_rate"`
+}
+
+// GetBroadcast reports a broadcast's message counts by status, its delivery
+// rate and, for broadcasts with shortened links, its clicks. A/B tests are
+// broken down per variant as well.
 //
-// POST /dlr
-// Body: { "provider_id": "...", "status": "delivered"|"failed" }
-func (h *Handler) HandleDLR(c *fiber.Ctx) error {
-	var req dlrRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+// GET /broadcasts/:id
+func (h *Handler) GetBroadcast(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid broadcast id"})
+	}
+
+	report, err := h.svc.GetBroadcastStats(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrBroadcastNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "broadcast not found"})
+		}
+		h.log.Error("get broadcast", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	broadcast := report.Broadcast
+	resp := broadcastResponse{
+		ID:           broadcast.ID.String(),
+		Name:         broadcast.Name,
+		Category:     broadcast.Category,
+		Sender:       broadcast.Sender,
+		ShortenLinks: broadcast.ShortenLinks,
+		CreatedAt:    broadcast.CreatedAt,
+		Stats:        toBroadcastStatsResponse(report.Stats),
 	}
+	for _, v := range report.Variants {
+		resp.Variants = append(resp.Variants, variantResponse{
+			Name:    v.Variant.Name,
+			Body:    v.Variant.Body,
+			Percent: v.Variant.Percent,
+			Stats:   toBroadcastStatsResponse(v.Stats),
+		})
+	}
+
+	return c.JSON(resp)
+}
 
-	if req.ProviderID == "" || req.Status == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "provider_id and status are required"})
+func toBroadcastStatsResponse(stats domain.BroadcastStats) broadcastStatsResponse {
+	return broadcastStatsResponse{
+		Total:            stats.Total,
+		Pending:          stats.Pending,
+		Queued:           stats.Queued,
+		Sent:             stats.Sent,
+		Delivered:        stats.Delivered,
+		Failed:           stats.Failed,
+		Suppressed:       stats.Suppressed,
+		DeliveryRate:     stats.DeliveryRate(),
+		Clicks:           stats.Clicks,
+		UniqueClicks:     stats.ClickedMessages,
+		ClickThroughRate: stats.ClickThroughRate(),
 	}
+}
 
-	providerID, err := uuid.Parse(req.ProviderID)
+// ── Broadcast jobs ────────────────────────────────────────────────────────────
+
+type jobAcceptedResponse struct {
+	JobID     string `json:"job_id"`
+	Status    string `json:"status"`
+	StatusURL string `json:"status_url"`
+}
+
+type jobResponse struct {
+	ID           string            `json:"id"`
+	Status       domain.JobStatus  `json:"status"`
+	BroadcastID  *string           `json:"broadcast_id,omitempty"`
+	TotalRows    int               `json:"total_rows"`
+	Processed    int               `json:"processed"`
+	Queued       int               `json:"queued"`
+	Rejected     int               `json:"rejected"`
+	Suppressed   int               `json:"suppressed"`
+	Capped       int               `json:"capped"`
+	Duplicates   int               `json:"duplicates"`
+	RejectedRows []app.RejectedRow `json:"rejected_rows"`
+	Error        string            `json:"error,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	StartedAt    *time.Time        `json:"started_at,omitempty"`
+	FinishedAt   *time.Time        `json:"finished_at,omitempty"`
+}
+
+func (h *Handler) jobAccepted(c *fiber.Ctx, job domain.BroadcastJob, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrListNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unknown list_ids"})
+	case errors.Is(err, domain.ErrInvalidSegment), errors.Is(err, domain.ErrSenderNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "provider_id must be a valid UUID"})
+		h.log.Error("submit broadcast job", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
 	}
 
-	dlr := ports.DLRPayload{
-		ProviderID: providerID,
-		Status:     statusFromString(req.Status),
+	return c.Status(fiber.StatusAccepted).JSON(jobAcceptedResponse{
+		JobID:     job.ID.String(),
+		Status:    string(job.Status),
+		StatusURL: "/api/jobs/" + job.ID.String(),
+	})
+}
+
+// GetJob reports the progress and validation errors of a broadcast job.
+//
+// GET /jobs/:id
+func (h *Handler) GetJob(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid job id"})
 	}
 
-	if err := h.svc.HandleDLR(c.Context(), dlr); err != nil {
-		h.log.Error("handle dlr", "err", err)
+	job, err := h.jobs.GetJob(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "job not found"})
+		}
+		h.log.Error("get job", "err", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
 	}
 
-	return c.SendStatus(fiber.StatusNoContent)
+	resp := jobResponse{
+		ID:           job.ID.String(),
+		Status:       job.Status,
+		TotalRows:    job.TotalRows,
+		Processed:    job.Queued + job.Rejected + job.Suppressed + job.Capped + job.Duplicates,
+		Queued:       job.Queued,
+		Rejected:     job.Rejected,
+		Suppressed:   job.Suppressed,
+		Capped:       job.Capped,
+		Duplicates:   job.Duplicates,
+		RejectedRows: []app.RejectedRow{},
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+	if job.BroadcastID != nil {
+		id := job.BroadcastID.String()
+		resp.BroadcastID = &id
+	}
+	if err := json.Unmarshal([]byte(job.Errors), &resp.RejectedRows); err != nil {
+		h.log.Error("decode job errors", "job_id", job.ID, "err", err)
+	}
+
+	return c.JSON(resp)
+}
+
+// validateVariants checks the A/B test variants of a request, which replace
+// its body.
+func validateVariants(body string, variants []domain.Variant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	if body != "" {
+		return fmt.Errorf("%w: use body or variants, not both", domain.ErrInvalidVariants)
+	}
+	return domain.ValidateVariants(variants)
+}
+
+// validCallbackURL accepts absolute http(s) URLs whose host is not obviously
+// internal. Names that resolve to private addresses are refused again by the
+// dispatcher when it connects.
+func validCallbackURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User != nil {
+		return false
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	switch {
+	case host == "":
+		return false
+	case host == "localhost" || strings.HasSuffix(host, ".localhost"):
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return domain.PublicIP(ip)
+	}
+	return true
+}
+
+// maxUploadFieldSize bounds each field of an upload form other than the file.
+const maxUploadFieldSize = 64 * 1024
+
+// errInvalidForm is returned for a malformed multipart upload.
+var errInvalidForm = errors.New("invalid multipart form")
+
+// uploadForm is a multipart upload read from the request body stream: its
+// fields, and its file spooled to a temporary file.
+type uploadForm struct {
+	fields map[string]string
+	file   *os.File // Nil when the form had no file
+}
+
+func (f *uploadForm) value(name string) string {
+	return f.fields[name]
+}
+
+// Close removes the spooled file.
+func (f *uploadForm) Close() {
+	if f.file != nil {
+		f.file.Close()
+		os.Remove(f.file.Name())
+	}
+}
+
+// readUploadForm reads a multipart/form-data body part by part, copying the
+// fileField part to a temporary file as it arrives, so a large upload is
+// never held in memory. Fields may come before or after the file.
+func readUploadForm(c *fiber.Ctx, fileField string) (*uploadForm, error) {
+	boundary := string(c.Request().Header.MultipartFormBoundary())
+	if boundary == "" {
+		return nil, fmt.Errorf("%w: expected multipart/form-data", errInvalidForm)
+	}
+
+	// The body arrives as a stream unless middleware.BodyLimit already read it.
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	form := &uploadForm{fields: make(map[string]string)}
+	if err := form.read(multipart.NewReader(body, boundary), fileField); err != nil {
+		form.Close()
+		return nil, err
+	}
+	return form, nil
 }
 
-func statusFromString(s string) domain.Status {
-	return domain.Status(s)
+func (f *uploadForm) read(mr *multipart.Reader, fileField string) error {
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", errInvalidForm, err)
+		}
+
+		name := part.FormName()
+		switch {
+		case name == fileField && f.file == nil:
+			if f.file, err = os.CreateTemp("", "broadcast-upload-*.csv"); err != nil {
+				return fmt.Errorf("create upload file: %w", err)
+			}
+			if _, err := io.Copy(f.file, part); err != nil {
+				return fmt.Errorf("%w: %v", errInvalidForm, err)
+			}
+
+		case part.FileName() == "":
+			value, err := io.ReadAll(io.LimitReader(part, maxUploadFieldSize+1))
+			if err != nil {
+				return fmt.Errorf("%w: %v", errInvalidForm, err)
+			}
+			if len(value) > maxUploadFieldSize {
+				return fmt.Errorf("%w: field %q is too large", errInvalidForm, name)
+			}
+			if _, ok := f.fields[name]; !ok {
+				f.fields[name] = string(value)
+			}
+		}
+		// Any other file part is skipped by NextPart.
+	}
+
+	if f.file != nil {
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("rewind upload file: %w", err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InboundHandler receives mobile-originated message webhooks from SMS providers.
+type InboundHandler struct {
+	inbound *app.InboundService
+	parsers map[string]ports.InboundParser
+	log     *slog.Logger
+}
+
+// NewInboundHandler wires up an InboundHandler with its dependencies.
+// parsers maps a provider name (the :provider route segment) to its format.
+func NewInboundHandler(inbound *app.InboundService, parsers map[string]ports.InboundParser, log *slog.Logger) *InboundHandler {
+	return &InboundHandler{inbound: inbound, parsers: parsers, log: log}
+}
+
+// Register mounts the inbound message routes, running mw (e.g. signature
+// verification) before the handler.
+func (h *InboundHandler) Register(router fiber.Router, mw ...fiber.Handler) {
+	handlers := append(mw, h.HandleInbound)
+	router.Post("/inbound", handlers...)
+	router.Post("/inbound/:provider", handlers...)
+}
+
+// HandleInbound parses an inbound message and enqueues it for the dlr-processor.
+// The body format depends on the provider segment of the route, see adapters/dlr.
+//
+// POST /inbound            (generic JSON: { "provider_id": "...", "from": "+66...", "to": "...", "body": "STOP" })
+// POST /inbound/:provider  (e.g. /inbound/twilio, /inbound/smpp)
+func (h *InboundHandler) HandleInbound(c *fiber.Ctx) error {
+	provider := c.Params("provider", defaultDLRProvider)
+
+	parser, ok := h.parsers[provider]
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "unknown provider"})
+	}
+
+	msg, err := parser.ParseInbound(string(c.Request().Header.ContentType()), queryValues(c), c.Body())
+	if err != nil {
+		h.log.Warn("parse inbound message", "provider", provider, "err", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	msg.Provider = provider
+
+	if err := h.inbound.AcceptInbound(c.Context(), msg); err != nil {
+		if errors.Is(err, domain.ErrInvalidPhone) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.log.Error("accept inbound message", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// queryValues returns the request's query parameters; malformed pairs are dropped.
+func queryValues(c *fiber.Ctx) url.Values {
+	values, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+	return values
+}
@@ -0,0 +1,56 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxUserAgentLen bounds the User-Agent stored with a click.
+const maxUserAgentLen = 512
+
+// LinkHandler redirects short links to their original URLs.
+type LinkHandler struct {
+	svc *app.LinkService
+	log *slog.Logger
+}
+
+// NewLinkHandler wires up a LinkHandler with its dependencies.
+func NewLinkHandler(svc *app.LinkService, log *slog.Logger) *LinkHandler {
+	return &LinkHandler{svc: svc, log: log}
+}
+
+// Register mounts the redirect route onto the given router.
+func (h *LinkHandler) Register(router fiber.Router) {
+	router.Get("/:code", h.Redirect)
+}
+
+// Redirect sends the visitor on to the link's original URL and records the
+// click. HEAD requests, which link previews often use, are not counted.
+//
+// GET /:code
+func (h *LinkHandler) Redirect(c *fiber.Ctx) error {
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = userAgent[:maxUserAgentLen]
+	}
+	userAgent = strings.ToValidUTF8(userAgent, "")
+
+	target, err := h.svc.Resolve(c.Context(), c.Params("code"), userAgent, c.Method() != fiber.MethodHead)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(fiber.StatusNotFound).SendString("link not found")
+		}
+		h.log.Error("resolve link", "err", err)
+		return c.Status(fiber.StatusInternalServerError).SendString("internal server error")
+	}
+
+	// Every click must reach us, so the redirect is never cached.
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Redirect(target, fiber.StatusFound)
+}
@@ -0,0 +1,132 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// MessageHandler holds the HTTP handlers for single messages.
+type MessageHandler struct {
+	svc *app.BroadcastService
+	log *slog.Logger
+}
+
+// NewMessageHandler wires up a MessageHandler with its dependencies.
+func NewMessageHandler(svc *app.BroadcastService, log *slog.Logger) *MessageHandler {
+	return &MessageHandler{svc: svc, log: log}
+}
+
+// Register mounts the message routes onto the given router.
+func (h *MessageHandler) Register(router fiber.Router) {
+	router.Post("/messages", h.SendMessage)
+	router.Get("/messages/:id", h.GetMessage)
+}
+
+type sendMessageRequest struct {
+	To       string `json:"to"`
+	Body     string `json:"body"`
+	Sender   string `json:"sender"`
+	Priority string `json:"priority"`
+}
+
+type messageResponse struct {
+	ID               string     `json:"id"`
+	BroadcastID      string     `json:"broadcast_id"`
+	To               string     `json:"to"`
+	Sender           string     `json:"sender,omitempty"`
+	Priority         string     `json:"priority"`
+	Variant          string     `json:"variant,omitempty"`
+	Status           string     `json:"status"`
+	ProviderID       string     `json:"provider_id,omitempty"`
+	ErrorCode        string     `json:"error_code,omitempty"`
+	ErrorDescription string     `json:"error_description,omitempty"`
+	DeliveredAt      *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// SendMessage queues a single transactional SMS without creating a
+// broadcast. The returned id is used with GET /messages/:id.
+//
+// POST /messages
+// Body: { "to": "+66812345678", "body": "...", "sender": "ACME", "priority": "normal" }
+func (h *MessageHandler) SendMessage(c *fiber.Ctx) error {
+	var req sendMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.To == "" || req.Body == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "to and body are required"})
+	}
+
+	priority, err := domain.ParsePriority(req.Priority)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	msg, err := h.svc.QueueSingleMessage(c.Context(), app.SingleMessageRequest{
+		To:       req.To,
+		Sender:   req.Sender,
+		Body:     req.Body,
+		Priority: priority,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidPhone), errors.Is(err, domain.ErrSenderNotFound),
+			errors.Is(err, domain.ErrSenderNotAllowed):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrRecipientSuppressed):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.log.Error("send message", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(toMessageResponse(msg))
+}
+
+// GetMessage returns a message's current delivery status.
+//
+// GET /messages/:id
+func (h *MessageHandler) GetMessage(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid message id"})
+	}
+
+	msg, err := h.svc.GetMessage(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.log.Error("get message", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.JSON(toMessageResponse(*msg))
+}
+
+func toMessageResponse(msg domain.Message) messageResponse {
+	return messageResponse{
+		ID:               msg.ID.String(),
+		BroadcastID:      msg.BroadcastID.String(),
+		To:               msg.To,
+		Sender:           msg.Sender,
+		Priority:         string(msg.Priority),
+		Variant:          msg.Variant,
+		Status:           string(msg.Status),
+		ProviderID:       msg.ProviderID,
+		ErrorCode:        msg.ErrorCode,
+		ErrorDescription: msg.ErrorDescription,
+		DeliveredAt:      msg.DeliveredAt,
+		CreatedAt:        msg.CreatedAt,
+		UpdatedAt:        msg.UpdatedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// OTPHandler holds the HTTP handlers for one-time passwords.
+type OTPHandler struct {
+	otps *app.OTPService
+	log  *slog.Logger
+}
+
+// NewOTPHandler wires up an OTPHandler with its dependencies.
+func NewOTPHandler(otps *app.OTPService, log *slog.Logger) *OTPHandler {
+	return &OTPHandler{otps: otps, log: log}
+}
+
+// Register mounts the OTP routes onto the given router.
+func (h *OTPHandler) Register(router fiber.Router) {
+	router.Post("/otp", h.SendOTP)
+	router.Post("/otp/verify", h.VerifyOTP)
+}
+
+type sendOTPRequest struct {
+	Phone  string `json:"phone"`
+	Sender string `json:"sender"`
+	Body   string `json:"body"`
+}
+
+type sendOTPResponse struct {
+	OTPID     string    `json:"otp_id"`
+	MessageID string    `json:"message_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SendOTP generates a one-time password and sends it on the priority lane.
+//
+// POST /otp
+// Body: { "phone": "+66812345678", "sender": "ACME", "body": "Your ACME code is {{code}}" }
+func (h *OTPHandler) SendOTP(c *fiber.Ctx) error {
+	var req sendOTPRequest
+	if err := c.BodyParser(&req); err != nil || req.Phone == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "phone is required"})
+	}
+
+	otp, err := h.otps.SendOTP(c.Context(), app.SendOTPRequest{Phone: req.Phone, Sender: req.Sender, Body: req.Body})
+	if err != nil {
+		return h.otpError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(sendOTPResponse{
+		OTPID:     otp.ID.String(),
+		MessageID: otp.MessageID.String(),
+		ExpiresAt: otp.ExpiresAt,
+	})
+}
+
+type verifyOTPRequest struct {
+	OTPID string `json:"otp_id"`
+	Code  string `json:"code"`
+}
+
+// VerifyOTP checks a code entered by the user. A wrong code answers 400
+// with the attempts left; too many wrong codes answer 429.
+//
+// POST /otp/verify
+// Body: { "otp_id": "...", "code": "123456" }
+func (h *OTPHandler) VerifyOTP(c *fiber.Ctx) error {
+	var req verifyOTPRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "otp_id and code are required"})
+	}
+	id, err := uuid.Parse(req.OTPID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid otp_id"})
+	}
+
+	otp, err := h.otps.VerifyOTP(c.Context(), id, req.Code)
+	if err != nil {
+		return h.otpError(c, err)
+	}
+
+	return c.JSON(fiber.Map{"verified": true, "phone": otp.Phone})
+}
+
+func (h *OTPHandler) otpError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrOTPNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrOTPMismatch):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "verified": false})
+	case errors.Is(err, domain.ErrOTPExpired), errors.Is(err, domain.ErrOTPUsed):
+		return c.Status(fiber.StatusGone).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrOTPLocked), errors.Is(err, domain.ErrOTPTooSoon):
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrRecipientSuppressed):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidPhone), errors.Is(err, domain.ErrInvalidOTPBody),
+		errors.Is(err, domain.ErrSenderNotFound), errors.Is(err, domain.ErrSenderNotAllowed):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	h.log.Error("otp", "err", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
@@ -0,0 +1,178 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SenderHandler holds the HTTP handlers for the sender ID registry.
+type SenderHandler struct {
+	senders *app.SenderService
+	log     *slog.Logger
+}
+
+// NewSenderHandler wires up a SenderHandler with its dependencies.
+func NewSenderHandler(senders *app.SenderService, log *slog.Logger) *SenderHandler {
+	return &SenderHandler{senders: senders, log: log}
+}
+
+// Register mounts the sender routes onto the given router.
+func (h *SenderHandler) Register(router fiber.Router) {
+	router.Post("/senders", h.CreateSender)
+	router.Get("/senders", h.ListSenders)
+	router.Get("/senders/:sender", h.GetSender)
+	router.Put("/senders/:sender", h.UpdateSender)
+	router.Delete("/senders/:sender", h.DeleteSender)
+}
+
+type senderRequest struct {
+	Sender       string   `json:"sender"`
+	Type         string   `json:"type"`
+	CountryCodes []string `json:"country_codes"`
+	Description  string   `json:"description"`
+}
+
+type senderResponse struct {
+	Sender       string    `json:"sender"`
+	Type         string    `json:"type"`
+	CountryCodes []string  `json:"country_codes"`
+	Description  string    `json:"description,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreateSender registers a sender ID and the countries it may send to.
+//
+// POST /senders
+// Body: { "sender": "ACME", "type": "alphanumeric", "country_codes": ["66", "65"], "description": "..." }
+func (h *SenderHandler) CreateSender(c *fiber.Ctx) error {
+	var req senderRequest
+	if err := c.BodyParser(&req); err != nil || req.Sender == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "sender, type and country_codes are required"})
+	}
+
+	sender, err := h.senders.CreateSender(c.Context(), domain.SenderID{
+		Sender:       req.Sender,
+		Type:         domain.SenderType(req.Type),
+		CountryCodes: req.CountryCodes,
+		Description:  req.Description,
+	})
+	if err != nil {
+		return h.senderError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(toSenderResponse(sender))
+}
+
+// ListSenders returns all registered senders.
+//
+// GET /senders
+func (h *SenderHandler) ListSenders(c *fiber.Ctx) error {
+	senders, err := h.senders.ListSenders(c.Context())
+	if err != nil {
+		return h.senderError(c, err)
+	}
+
+	resp := make([]senderResponse, 0, len(senders))
+	for _, s := range senders {
+		resp = append(resp, toSenderResponse(s))
+	}
+	return c.JSON(resp)
+}
+
+// GetSender returns one registered sender.
+//
+// GET /senders/:sender
+func (h *SenderHandler) GetSender(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("sender"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid sender"})
+	}
+
+	sender, err := h.senders.GetSender(c.Context(), name)
+	if err != nil {
+		return h.senderError(c, err)
+	}
+
+	return c.JSON(toSenderResponse(sender))
+}
+
+// UpdateSender replaces a sender's type, country codes and description.
+//
+// PUT /senders/:sender
+// Body: { "type": "alphanumeric", "country_codes": ["66"], "description": "..." }
+func (h *SenderHandler) UpdateSender(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("sender"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid sender"})
+	}
+
+	var req senderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	sender, err := h.senders.UpdateSender(c.Context(), domain.SenderID{
+		Sender:       name,
+		Type:         domain.SenderType(req.Type),
+		CountryCodes: req.CountryCodes,
+		Description:  req.Description,
+	})
+	if err != nil {
+		return h.senderError(c, err)
+	}
+
+	return c.JSON(toSenderResponse(sender))
+}
+
+// DeleteSender unregisters a sender. Existing broadcasts are unaffected.
+//
+// DELETE /senders/:sender
+func (h *SenderHandler) DeleteSender(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("sender"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid sender"})
+	}
+
+	if err := h.senders.DeleteSender(c.Context(), name); err != nil {
+		return h.senderError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *SenderHandler) senderError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrSenderNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrSenderExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidSender):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	h.log.Error("senders", "err", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
+
+func toSenderResponse(s domain.SenderID) senderResponse {
+	codes := s.CountryCodes
+	if codes == nil {
+		codes = []string{}
+	}
+	return senderResponse{
+		Sender:       s.Sender,
+		Type:         string(s.Type),
+		CountryCodes: codes,
+		Description:  s.Description,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
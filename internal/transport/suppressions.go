@@ -0,0 +1,178 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"golang-sms-broadcast/internal/app"
+	"golang-sms-broadcast/internal/domain"
+	"golang-sms-broadcast/internal/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SuppressionHandler holds the HTTP handlers for the opt-out list.
+type SuppressionHandler struct {
+	suppressions *app.SuppressionService
+	log          *slog.Logger
+}
+
+// NewSuppressionHandler wires up a SuppressionHandler with its dependencies.
+func NewSuppressionHandler(suppressions *app.SuppressionService, log *slog.Logger) *SuppressionHandler {
+	return &SuppressionHandler{suppressions: suppressions, log: log}
+}
+
+// Register mounts the suppression routes onto the given router.
+func (h *SuppressionHandler) Register(router fiber.Router) {
+	router.Post("/suppressions", h.AddSuppressions)
+	router.Post("/suppressions/import", h.ImportSuppressions)
+	router.Get("/suppressions", h.ListSuppressions)
+	router.Delete("/suppressions", h.RemoveSuppression)
+}
+
+type addSuppressionsRequest struct {
+	Phones []string `json:"phones"`
+	Sender string   `json:"sender"` // Empty for the global list
+	Reason string   `json:"reason"`
+}
+
+type suppressionResponse struct {
+	Phone     string    `json:"phone"`
+	Sender    string    `json:"sender"`
+	Reason    string    `json:"reason,omitempty"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddSuppressions puts numbers on the global list or one sender's list.
+//
+// POST /suppressions
+// Body: { "phones": ["+66812345678"], "sender": "", "reason": "complaint" }
+func (h *SuppressionHandler) AddSuppressions(c *fiber.Ctx) error {
+	var req addSuppressionsRequest
+	if err := c.BodyParser(&req); err != nil || len(req.Phones) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "phones are required"})
+	}
+
+	added, err := h.suppressions.AddSuppressions(c.Context(), req.Phones, req.Sender, req.Reason, domain.SuppressionSourceAPI)
+	if err != nil {
+		return h.suppressionError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"added": added})
+}
+
+type removeSuppressionRequest struct {
+	Phone  string `json:"phone"`
+	Sender string `json:"sender"`
+}
+
+// RemoveSuppression takes a number off the global list or one sender's list.
+//
+// DELETE /suppressions
+// Body: { "phone": "+66812345678", "sender": "" }
+func (h *SuppressionHandler) RemoveSuppression(c *fiber.Ctx) error {
+	var req removeSuppressionRequest
+	if err := c.BodyParser(&req); err != nil || req.Phone == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "phone is required"})
+	}
+
+	if err := h.suppressions.RemoveSuppression(c.Context(), req.Phone, req.Sender); err != nil {
+		return h.suppressionError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListSuppressions returns a page of the list, newest first. Without sender
+// entries of every sender are returned; sender= (empty) selects the global list.
+//
+// GET /suppressions?sender=...&phone=...&limit=100&offset=0
+func (h *SuppressionHandler) ListSuppressions(c *fiber.Ctx) error {
+	filter := ports.SuppressionFilter{
+		Phone:  c.Query("phone"),
+		Limit:  c.QueryInt("limit", 100),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if filter.Limit <= 0 || filter.Limit > 1000 {
+		filter.Limit = 100
+	}
+	if c.Context().QueryArgs().Has("sender") {
+		sender := c.Query("sender")
+		filter.Sender = &sender
+	}
+
+	entries, err := h.suppressions.ListSuppressions(c.Context(), filter)
+	if err != nil {
+		return h.suppressionError(c, err)
+	}
+
+	resp := make([]suppressionResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, suppressionResponse{
+			Phone:     e.Phone,
+			Sender:    e.Sender,
+			Reason:    e.Reason,
+			Source:    e.Source,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return c.JSON(resp)
+}
+
+type importSuppressionsResponse struct {
+	Added        int64             `json:"added"`
+	Rejected     int               `json:"rejected"`
+	RejectedRows []app.RejectedRow `json:"rejected_rows"`
+}
+
+// ImportSuppressions bulk-loads numbers from a CSV file.
+//
+// POST /suppressions/import (multipart/form-data)
+// Fields: file, phone_column (optional, default "phone"), sender (optional), reason (optional)
+func (h *SuppressionHandler) ImportSuppressions(c *fiber.Ctx) error {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		h.log.Error("open upload", "err", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+	defer file.Close()
+
+	result, err := h.suppressions.ImportSuppressions(c.Context(), app.ImportSuppressionsRequest{
+		CSV:         file,
+		PhoneColumn: c.FormValue("phone_column"),
+		Sender:      c.FormValue("sender"),
+		Reason:      c.FormValue("reason"),
+	})
+	if err != nil {
+		return h.suppressionError(c, err)
+	}
+
+	resp := importSuppressionsResponse{
+		Added:        result.Added,
+		Rejected:     result.Rejected,
+		RejectedRows: result.RejectedRows,
+	}
+	if resp.RejectedRows == nil {
+		resp.RejectedRows = []app.RejectedRow{}
+	}
+	return c.JSON(resp)
+}
+
+func (h *SuppressionHandler) suppressionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrSuppressionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidPhone), errors.Is(err, domain.ErrInvalidUpload):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	h.log.Error("suppressions", "err", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
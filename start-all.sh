@@ -10,11 +10,19 @@ if [[ "$OSTYPE" == "darwin"* ]]; then
     sleep 1
     osascript -e 'tell application "Terminal" to do script "cd '"$(pwd)"' && go run cmd/dlr-webhook/main.go"'
     sleep 1
-    osascript -e 'tell application "Terminal" to do script "cd '"$(pwd)"' && go run cmd/mock-sms-provider/main.go"'
+    osascript -e 'tell application "Terminal" to do script "cd '"$(pwd)"' && go run cmd/mock-sms-provider/*.go"'
     sleep 1
     osascript -e 'tell application "Terminal" to do script "cd '"$(pwd)"' && go run cmd/outbox-publisher/main.go"'
     sleep 1
     osascript -e 'tell application "Terminal" to do script "cd '"$(pwd)"' && go run cmd/sender-worker/main.go"'
+    sleep 1
+    osascript -e 'tell application "Terminal" to do script "cd '"$(pwd)"' && go run cmd/dlr-processor/main.go"'
+    sleep 1
+    osascript -e 'tell application "Terminal" to do script "cd '"$(pwd)"' && go run cmd/callback-dispatcher/main.go"'
+    sleep 1
+    osascript -e 'tell application "Terminal" to do script "cd '"$(pwd)"' && go run cmd/job-worker/main.go"'
+    sleep 1
+    osascript -e 'tell application "Terminal" to do script "cd '"$(pwd)"' && go run cmd/link-redirect/main.go"'
     
     echo "✅ All services started in separate terminals"
 else
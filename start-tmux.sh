@@ -11,18 +11,26 @@ if [ $? != 0 ]; then
     # Create new session with first service
     tmux new-session -d -s $SESSION -n services
     
-    # Split into 5 panes
+    # Split into 9 panes
     tmux split-window -h -t $SESSION:services
     tmux split-window -v -t $SESSION:services.0
     tmux split-window -v -t $SESSION:services.2
     tmux split-window -v -t $SESSION:services.0
+    tmux split-window -v -t $SESSION:services.4
+    tmux split-window -v -t $SESSION:services.5
+    tmux split-window -v -t $SESSION:services.6
+    tmux split-window -v -t $SESSION:services.7
     
     # Run services in each pane
     tmux send-keys -t $SESSION:services.0 'go run cmd/broadcast-api/main.go' C-m
     tmux send-keys -t $SESSION:services.1 'go run cmd/dlr-webhook/main.go' C-m
-    tmux send-keys -t $SESSION:services.2 'go run cmd/mock-sms-provider/main.go' C-m
+    tmux send-keys -t $SESSION:services.2 'go run cmd/mock-sms-provider/*.go' C-m
     tmux send-keys -t $SESSION:services.3 'go run cmd/outbox-publisher/main.go' C-m
     tmux send-keys -t $SESSION:services.4 'go run cmd/sender-worker/main.go' C-m
+    tmux send-keys -t $SESSION:services.5 'go run cmd/dlr-processor/main.go' C-m
+    tmux send-keys -t $SESSION:services.6 'go run cmd/callback-dispatcher/main.go' C-m
+    tmux send-keys -t $SESSION:services.7 'go run cmd/job-worker/main.go' C-m
+    tmux send-keys -t $SESSION:services.8 'go run cmd/link-redirect/main.go' C-m
     
     # Adjust layout
     tmux select-layout -t $SESSION:services tiled